@@ -0,0 +1,144 @@
+package pdfb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// anchor is used to define a named location in the document, an anchor
+// that has been referred to but not yet placed is not defined
+type anchor struct {
+	link    int
+	page    int
+	label   string
+	text    string
+	number  string
+	defined bool
+}
+
+// ref is used to define a reference written before its anchor, the
+// alias is replaced with the text of the reference when the document
+// is output, or when the text was known from an earlier pass of
+// Generate, the text is written and checked when the document is output
+type ref struct {
+	name   string
+	format string
+	alias  string
+	text   string
+}
+
+// Anchor is used to mark the current position with a name, so that it can
+// be referred to with Ref
+// The text and number of an anchor are those of the heading it comes after.
+// Headings can be given an anchor using HeadingOptions.
+func (p *Pdfb) Anchor(name string) {
+	p.setAnchor(name, p.GetY(), p.pdf.PageNo())
+}
+
+// Ref is used to write a reference to an anchor, which links to the anchor
+// In format, {page} is replaced with the page number of the anchor, {text}
+// with the text of its heading and {number} with the number of its heading.
+// The format defaults to "{page}".
+// References to anchors further on in the document are filled in when the
+// document is output, since their text isn't known when they are written the
+// text after them may not line up exactly, unless the document is built using
+// Generate. An error wrapping ErrUnresolvedRef is set when the document is
+// output if the anchor is never placed.
+//
+// Eg. p.Ref("scope", "section {number} on page {page}")
+func (p *Pdfb) Ref(name, format string) {
+	if format == "" {
+		format = "{page}"
+	}
+
+	a := p.getAnchor(name)
+
+	var text string
+	if a.defined {
+		text = a.format(format)
+	} else if estimate, ok := p.anchorEstimates[name]; ok {
+		// known from the last pass of Generate, checked when the
+		// document is output
+		text = estimate.format(format)
+		p.refs = append(p.refs, ref{name: name, format: format, text: text})
+	} else {
+		// filled in when the document is output
+		text = fmt.Sprintf("{ref:%d}", len(p.refs))
+		p.refs = append(p.refs, ref{name: name, format: format, alias: text})
+	}
+	p.pdf.WriteLinkID(p.lineHeight, text, a.link)
+
+	p.checkpoint("Reference written")
+}
+
+// used to get an anchor by name, the anchor is created if it doesn't
+// exist yet so that references to it can link to it
+func (p *Pdfb) getAnchor(name string) *anchor {
+	a, ok := p.anchors[name]
+	if !ok {
+		a = &anchor{link: p.pdf.AddLink()}
+		p.anchors[name] = a
+	}
+	return a
+}
+
+// used to place an anchor at a position
+func (p *Pdfb) setAnchor(name string, y float64, page int) {
+	a := p.getAnchor(name)
+	if a.defined {
+		p.SetErrorf("%w: %s", ErrDuplicateAnchor, name)
+		return
+	}
+
+	a.defined = true
+	a.page = page
+	a.label = p.pageLabel(page)
+	p.pdf.SetLink(a.link, y, page)
+
+	// take the text and number of the heading the anchor comes after
+	if len(p.headings) > 0 {
+		h := p.headings[len(p.headings)-1]
+		a.text = h.title
+		a.number = h.number
+	}
+
+	p.checkpoint("Anchor set")
+}
+
+// used to fill in the references that were written before their anchors,
+// the layout isn't settled while any were written without their text
+func (p *Pdfb) resolveRefs() {
+	for _, r := range p.refs {
+		a := p.anchors[r.name]
+		if !a.defined {
+			p.SetErrorf("%w: %s", ErrUnresolvedRef, r.name)
+			return
+		}
+		if r.alias != "" {
+			p.pdf.RegisterAlias(r.alias, a.format(r.format))
+			p.settled = false
+		} else if r.text != a.format(r.format) {
+			p.settled = false
+		}
+	}
+}
+
+// used to copy the anchors that have been placed
+func (p *Pdfb) placedAnchors() map[string]anchor {
+	anchors := map[string]anchor{}
+	for name, a := range p.anchors {
+		if a.defined {
+			anchors[name] = *a
+		}
+	}
+	return anchors
+}
+
+// used to write the text of a reference to an anchor
+func (a *anchor) format(format string) string {
+	return strings.NewReplacer(
+		"{page}", a.label,
+		"{text}", a.text,
+		"{number}", a.number,
+	).Replace(format)
+}
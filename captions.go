@@ -0,0 +1,79 @@
+package pdfb
+
+import "fmt"
+
+// used to write the text of a numbered caption, eg. Figure 3: A chart
+func captionText(label string, number int, caption string) string {
+	return fmt.Sprintf("%s %d: %s", label, number, caption)
+}
+
+// used to add a caption to the figures or tables at the cursor, so that
+// it is listed with a link back to it
+func (p *Pdfb) addCaption(entries *[]heading, text string) {
+	link := p.pdf.AddLink()
+	p.pdf.SetLink(link, p.GetY(), p.pdf.PageNo())
+	*entries = append(*entries, heading{
+		text:  text,
+		title: text,
+		level: 1,
+		page:  p.pdf.PageNo(),
+		link:  link,
+	})
+}
+
+// used to get the height of a caption in the caption style of the theme,
+// including the space around it
+func (p *Pdfb) captionHeight(text string) float64 {
+	currentFont := p.fontCopy(p.font)
+	currentLH := p.lineHeight
+
+	p.SetFont(p.theme.Caption.Font)
+	left, right := p.contentEdges()
+	h := float64(len(p.splitText(text, right-left))+1) * p.lineHeight
+
+	p.SetFont(currentFont)
+	p.SetLineHeight(currentLH)
+
+	return h
+}
+
+// used to write a caption centred under a figure or above a table, in the
+// caption style of the theme, with half a line of space either side
+func (p *Pdfb) writeCaption(text string) {
+	currentFont := p.fontCopy(p.font)
+	currentLH := p.lineHeight
+	currentFG := p.foreground
+
+	p.useTextStyle(p.theme.Caption)
+	left, right := p.contentEdges()
+	p.SetY(p.GetY() + p.lineHeight/2)
+	p.SetX(left)
+	p.pdf.MultiCell(right-left, p.lineHeight, text, "", "C", false)
+	p.SetY(p.GetY() + p.lineHeight/2)
+
+	p.SetFont(currentFont)
+	p.SetLineHeight(currentLH)
+	p.SetForeground(currentFG)
+
+	p.checkpoint("Caption printed")
+}
+
+// ListOfFigures is used to set aside pages for a list of the figures with
+// captions, which is written when the document is output
+// It works the same way as ToC, the title defaults to "List of Figures".
+func (p *Pdfb) ListOfFigures(options ...ToCOptions) {
+	p.addListing(p.mergeToCOptions(options, "List of Figures", []ToCLevelStyle{{}}), func() []heading {
+		return p.figures
+	})
+	p.checkpoint("List of figures pages added")
+}
+
+// ListOfTables is used to set aside pages for a list of the tables with
+// captions, which is written when the document is output
+// It works the same way as ToC, the title defaults to "List of Tables".
+func (p *Pdfb) ListOfTables(options ...ToCOptions) {
+	p.addListing(p.mergeToCOptions(options, "List of Tables", []ToCLevelStyle{{}}), func() []heading {
+		return p.tables
+	})
+	p.checkpoint("List of tables pages added")
+}
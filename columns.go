@@ -0,0 +1,99 @@
+package pdfb
+
+// SetColumns is used to flow content down columns, text flows to the
+// bottom of each column before moving on to the next, and only moves onto
+// a new page after the last column. Columns start at the cursor and are
+// separated by the gutter. Use a count of 1 to go back to a single column,
+// the cursor is then moved below the longest column.
+func (p *Pdfb) SetColumns(count int, gutter float64) {
+	if count < 1 {
+		count = 1
+	}
+
+	// end the current columns
+	if p.columns > 1 {
+		if y := p.GetY(); y > p.columnBottom {
+			p.columnBottom = y
+		}
+		left, right := p.sideMargins(p.pdf.PageNo())
+		p.pdf.SetLeftMargin(left)
+		p.pdf.SetRightMargin(right)
+		p.pdf.SetXY(left, p.columnBottom)
+	}
+
+	p.columns = count
+	p.columnGutter = gutter
+
+	if count > 1 {
+		p.startColumns()
+	}
+
+	p.checkpoint("Columns set")
+}
+
+// GetColumns is used to get the number of columns and the gutter between them
+func (p *Pdfb) GetColumns() (count int, gutter float64) {
+	return p.columns, p.columnGutter
+}
+
+// GetColumnWidth is used to get the width of a column, which is the width
+// between the margins when columns aren't being used
+func (p *Pdfb) GetColumnWidth() float64 {
+	left, right := p.sideMargins(p.pdf.PageNo())
+	return (p.GetPageWidth() - left - right - p.columnGutter*float64(p.columns-1)) / float64(p.columns)
+}
+
+// used to start the columns at the cursor, in the first column
+func (p *Pdfb) startColumns() {
+	indent := p.marginIndent()
+	p.column = 0
+	p.columnTop = p.GetY()
+	p.columnBottom = p.columnTop
+	p.setColumnMargins()
+	left, _ := p.contentEdges()
+	p.SetX(left + indent)
+}
+
+// used to set the margins to the edges of the current column, so that
+// text wraps within it
+func (p *Pdfb) setColumnMargins() {
+	w := p.GetColumnWidth()
+	margin, _ := p.sideMargins(p.pdf.PageNo())
+	left := margin + float64(p.column)*(w+p.columnGutter)
+	p.pdf.SetLeftMargin(left)
+	p.pdf.SetRightMargin(p.GetPageWidth() - left - w)
+}
+
+// used by the pdf when the bottom of the page is reached, moves on to the
+// next column if there is one and returns false so that no page is added
+func (p *Pdfb) acceptPageBreak() bool {
+	if p.columns < 2 {
+		p.keepXAcrossPages()
+		return true
+	}
+
+	// the cursor keeps its indent from the edge of the column
+	indent := p.marginIndent()
+
+	// after the last column, the new page starts in the first column,
+	// the cursor is moved here as the pdf keeps x across the page break
+	if p.column >= p.columns-1 {
+		p.column = 0
+		p.setColumnMargins()
+		left, _ := p.contentEdges()
+		p.pdf.SetX(left + indent)
+		p.keepXAcrossPages()
+		return true
+	}
+
+	if y := p.GetY(); y > p.columnBottom {
+		p.columnBottom = y
+	}
+	p.column++
+	p.setColumnMargins()
+	left, _ := p.contentEdges()
+	p.pdf.SetXY(left+indent, p.columnTop)
+
+	p.checkpoint("Column started")
+	return false
+}
@@ -0,0 +1,51 @@
+package pdfb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors that can be reported by the builder, these can be checked
+// against the error returned by Error using errors.Is
+var (
+	ErrInvalidPageSize     = errors.New("invalid page size")
+	ErrInvalidOrientation  = errors.New("invalid orientation")
+	ErrInvalidUnit         = errors.New("invalid unit")
+	ErrInvalidHeadingLevel = errors.New("invalid heading level")
+	ErrInvalidAlign        = errors.New("invalid alignment")
+	ErrInvalidFontStyle    = errors.New("invalid font style")
+	ErrImageNotFound       = errors.New("image could not be located")
+	ErrToCOverflow         = errors.New("contents do not fit on the pages set aside for them")
+	ErrDuplicateAnchor     = errors.New("anchor already defined")
+	ErrUnresolvedRef       = errors.New("reference to undefined anchor")
+	ErrLayoutNotSettled    = errors.New("layout did not settle")
+)
+
+// SetError is used to set the error state of the document, only the first
+// error is kept, and once set, further drawing operations have no effect
+func (p *Pdfb) SetError(err error) {
+	if err == nil || p.err != nil {
+		return
+	}
+	p.err = err
+	p.pdf.SetError(err)
+	p.logf(LogError, "%s", err)
+}
+
+// SetErrorf is used to set the error state of the document using a format string
+func (p *Pdfb) SetErrorf(format string, a ...interface{}) {
+	p.SetError(fmt.Errorf(format, a...))
+}
+
+// Err is used to check whether an error has occurred
+func (p *Pdfb) Err() bool {
+	return p.Error() != nil
+}
+
+// Error is used to get the first error that occurred while building the document
+func (p *Pdfb) Error() error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.pdf.Error()
+}
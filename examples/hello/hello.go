@@ -1,6 +1,8 @@
 package main
 
 import (
+	"log"
+
 	"github.com/barjoio/pdfb"
 )
 
@@ -48,14 +50,14 @@ func main() {
 	//
 
 	pdf.SetHeader(
-		"Inter",
+		"Helvetica",
 		pdfb.TextAlign{Text: "Left text", Align: "Left"},
 		pdfb.TextAlign{Text: "Centre text", Align: "c"},
 		pdfb.TextAlign{Text: "Right text", Align: "right"},
 	)
 
 	pdf.SetFooter(
-		"Inter",
+		"Helvetica",
 		pdfb.TextAlign{Text: "Page {page} of {pages}", Align: "Centre"},
 	)
 
@@ -63,8 +65,11 @@ func main() {
 	//	Table of Contents
 	//
 
+	// one page is set aside for the contents, more can be set aside
+	// using ToCOptions.Pages, or the document can be built using Generate
+	// to set aside as many pages as the contents need
 	pdf.Page()
-	pdf.ToC(1)
+	pdf.ToC()
 
 	//
 	//	Headings
@@ -141,7 +146,7 @@ func main() {
 	pdf.SetFont(pdfb.Font{Family: "RobotoMono"})
 	pdf.Paragraph("Exercitation mollit veniam velit ex aliquip occaecat commodo Lorem fugiat. Occaecat voluptate Lorem sint consequat consequat incididunt consectetur elit aliqua id. Culpa dolor irure culpa sint cupidatat aliqua sint excepteur laborum. Aliqua ea cupidatat ut irure officia in proident incididunt exercitation anim amet. Ea deserunt ex Lorem consequat labore Lorem deserunt consequat ad aute cupidatat Lorem. Tempor voluptate quis consequat exercitation est ex qui dolore est consectetur est deserunt ut nostrud.")
 
-	pdf.SetFont(pdfb.Font{Family: "Inter"})
+	pdf.SetFont(pdfb.Font{Family: "Helvetica"})
 
 	pdf.Write("Here is some ")
 	pdf.BoldLn("bold text.")
@@ -186,5 +191,7 @@ func main() {
 	pdf.Hyperlink("hyperlink", "https://github.com/barjoio/pdfb")
 	pdf.WriteLn(" to the Pdfb repo.")
 
-	pdf.SaveAs("examples/hello/hello.pdf")
+	if err := pdf.SaveAs("examples/hello/hello.pdf"); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -5,9 +5,12 @@ import (
 	"strings"
 
 	"github.com/barjoio/utils/colour"
-	"github.com/barjoio/utils/log"
 )
 
+// the font family used by default, Inter is not bundled so
+// one of the core PDF fonts is used instead
+const defaultFontFamily = "Helvetica"
+
 // var stdFonts = []string{"courier", "helvetica", "arial", "times", "symbol", "zapfdingbats"}
 
 // Font defines a font
@@ -59,7 +62,7 @@ func (p *Pdfb) SetFont(font Font) {
 		font.Family = p.font.Family
 	}
 	if strings.ToLower(font.Family) == "default" {
-		font.Family = "Inter"
+		font.Family = defaultFontFamily
 	}
 
 	// call this before settings the p.font, since SetFontSize uses a comparison
@@ -69,13 +72,8 @@ func (p *Pdfb) SetFont(font Font) {
 	// call this after SetFontSize to set the new p.font
 	p.font = font
 
-	// set font within pdf
+	// set font within pdf, an undefined font is kept as the pdf error
 	p.pdf.SetFont(font.Family, p.makeFontStyleStr(), font.Size)
-
-	// check for errors re: fonts
-	if p.pdf.Err() {
-		log.ErrorFatal("%s", p.pdf.Error())
-	}
 }
 
 // GetFont is used to get the font
@@ -117,7 +115,8 @@ func (p *Pdfb) ImportFont(fontName, fontDir string, fontStyles []FontStyle) {
 		case style == "bi" || style == "bolditalic":
 			styleStr += "bi"
 		default:
-			log.ErrorFatal("Invalid font style supplied to ImportFont (%s)", fontStyle.Style)
+			p.SetErrorf("%w: %s", ErrInvalidFontStyle, fontStyle.Style)
+			return
 		}
 
 		p.pdf.AddUTF8Font(fontName, styleStr, path.Join(fontDir, fontStyle.File))
@@ -139,13 +138,13 @@ func (p *Pdfb) GetForeground() string {
 
 // Bold is used to print bold text
 func (p *Pdfb) Bold(str string) {
+	currentFont := p.fontCopy(p.font)
 	p.font.Bold = true
 	p.SetFont(p.font)
 
-	p.Write(str)
+	p.Write("%s", str)
 
-	p.font.Bold = false
-	p.SetFont(p.font)
+	p.SetFont(currentFont)
 
 	p.checkpoint("Bold text written")
 }
@@ -158,13 +157,13 @@ func (p *Pdfb) BoldLn(str string) {
 
 // Italic is used to print italic text
 func (p *Pdfb) Italic(str string) {
+	currentFont := p.fontCopy(p.font)
 	p.font.Italic = true
 	p.SetFont(p.font)
 
-	p.Write(str)
+	p.Write("%s", str)
 
-	p.font.Italic = false
-	p.SetFont(p.font)
+	p.SetFont(currentFont)
 }
 
 // ItalicLn is used to print italic text, then print new line
@@ -175,15 +174,14 @@ func (p *Pdfb) ItalicLn(str string) {
 
 // BoldItalic is used to print bold italic text
 func (p *Pdfb) BoldItalic(str string) {
+	currentFont := p.fontCopy(p.font)
 	p.font.Bold = true
 	p.font.Italic = true
 	p.SetFont(p.font)
 
-	p.Write(str)
+	p.Write("%s", str)
 
-	p.font.Bold = false
-	p.font.Italic = false
-	p.SetFont(p.font)
+	p.SetFont(currentFont)
 }
 
 // BoldItalicLn is used to print bold italic text, then print new line
@@ -0,0 +1,205 @@
+package pdfb
+
+// footnote is used to define the text of a footnote that is printed at the
+// bottom of a page, a footnote that continues from the last page has no marker
+type footnote struct {
+	marker     string
+	lines      []string
+	lineHeight float64
+	indent     float64
+}
+
+// used to get the height of the lines of a footnote
+func (f footnote) height() float64 {
+	return float64(len(f.lines)) * f.lineHeight
+}
+
+// used to split a footnote after its first n lines, the rest continues
+// without the marker
+func (f footnote) split(n int) (footnote, footnote) {
+	rest := footnote{lines: f.lines[n:], lineHeight: f.lineHeight, indent: f.indent}
+	f.lines = f.lines[:n]
+	return f, rest
+}
+
+// SetFootnoteNumbering is used to set how footnotes are numbered, numbers
+// either run through the whole document or start again on each page
+func (p *Pdfb) SetFootnoteNumbering(format NumberFormat, perPage bool) {
+	p.footnoteFormat = format
+	p.footnotePerPage = perPage
+}
+
+// Footnote is used to write a footnote marker at the cursor, and print the
+// text of the footnote at the bottom of the page in the footnote style of
+// the theme
+// Space is set aside for the text above the footer, and text that doesn't fit
+// on the page continues at the bottom of the next page.
+func (p *Pdfb) Footnote(text string) {
+	// number the footnote
+	page := p.pdf.PageNo()
+	if p.footnotePerPage && page != p.footnotePage {
+		p.footnoteCount = 0
+	}
+	p.footnotePage = page
+	p.footnoteCount++
+	marker := p.footnoteFormat.Format(p.footnoteCount)
+
+	// write the marker raised and smaller than the text
+	p.pdf.SubWrite(p.lineHeight, marker, p.font.Size*0.6, p.font.Size*0.4, 0, "")
+
+	// split the text into lines in the footnote style
+	currentFont := p.fontCopy(p.font)
+	currentLH := p.lineHeight
+	p.SetFont(p.theme.Footnote.Font)
+	note := footnote{marker: marker, lineHeight: p.lineHeight}
+	note.indent = p.pdf.GetStringWidth(marker + " ")
+	left, right := p.sideMargins(p.pdf.PageNo())
+	width := p.GetPageWidth() - left - right - note.indent
+	note.lines = p.splitText(text, width)
+	p.SetFont(currentFont)
+	p.SetLineHeight(currentLH)
+
+	// footnotes after one that was carried over are carried over too,
+	// so that they stay in order
+	if len(p.footnoteCarry) > 0 {
+		p.carryFootnote(note)
+		return
+	}
+
+	// the lines that fit above the footer, below the line with the marker
+	space := p.footnoteSpace
+	if len(p.footnotes) == 0 {
+		space += note.lineHeight // separator
+	}
+	available := p.GetPageHeight() - p.footerSpace() - space - (p.GetY() + p.lineHeight)
+	fit := int(available / note.lineHeight)
+	if fit > len(note.lines) {
+		fit = len(note.lines)
+	}
+	if fit < 1 {
+		p.carryFootnote(note)
+		return
+	}
+
+	// the rest of the text continues on the next page
+	if fit < len(note.lines) {
+		var rest footnote
+		note, rest = note.split(fit)
+		p.carryFootnote(rest)
+	}
+	p.addFootnote(note)
+
+	p.checkpoint("Footnote added")
+}
+
+// used to add a footnote to the current page, and set aside space for it
+// above the footer
+func (p *Pdfb) addFootnote(note footnote) {
+	if len(p.footnotes) == 0 {
+		p.footnoteSpace += note.lineHeight // separator
+	}
+	p.footnotes = append(p.footnotes, note)
+	p.footnoteSpace += note.height()
+	p.setPageBreak()
+}
+
+// used to carry a footnote over to the next page, split into parts that
+// each fit on a page without any other footnotes
+func (p *Pdfb) carryFootnote(note footnote) {
+	fit := p.footnoteLinesFit(note, note.lineHeight) // separator
+	if fit < 1 {
+		fit = 1
+	}
+	for fit < len(note.lines) {
+		var part footnote
+		part, note = note.split(fit)
+		p.footnoteCarry = append(p.footnoteCarry, part)
+	}
+	p.footnoteCarry = append(p.footnoteCarry, note)
+}
+
+// used to get the number of lines of a footnote that fit on the page between
+// the header and the footer, once space has been set aside
+func (p *Pdfb) footnoteLinesFit(note footnote, space float64) int {
+	available := p.GetPageHeight() - p.footerSpace() - p.topSpace() - space
+	return int(available / note.lineHeight)
+}
+
+// used at the start of a page to add the footnotes carried over from the
+// last page, as many lines as fit on the page
+// At least one line is added to each page, so that the footnotes always end.
+func (p *Pdfb) startFootnotes() {
+	carry := p.footnoteCarry
+	p.footnotes, p.footnoteCarry, p.footnoteSpace = nil, nil, 0
+
+	for i, note := range carry {
+		space := p.footnoteSpace
+		if len(p.footnotes) == 0 {
+			space += note.lineHeight // separator
+		}
+		fit := p.footnoteLinesFit(note, space)
+		if fit < 1 && len(p.footnotes) == 0 {
+			fit = 1
+		}
+		if fit >= len(note.lines) {
+			p.addFootnote(note)
+			continue
+		}
+
+		// the lines that fit are added, and the rest of the note continues
+		// on the next page along with the footnotes after it
+		if fit > 0 {
+			var rest footnote
+			note, rest = note.split(fit)
+			p.addFootnote(note)
+			carry[i] = rest
+		}
+		p.footnoteCarry = carry[i:]
+		break
+	}
+	p.setPageBreak()
+}
+
+// used at the end of a page to print its footnotes above the footer,
+// under a separator
+func (p *Pdfb) printFootnotes() {
+	if len(p.footnotes) == 0 {
+		return
+	}
+
+	// copy current font and foreground
+	currentFont := p.fontCopy(p.font)
+	currentLH := p.lineHeight
+	currentFG := p.foreground
+	currentX, currentY := p.GetX(), p.GetY()
+
+	// separator, a third of the width of the page
+	left, right := p.sideMargins(p.pdf.PageNo())
+	y := p.GetPageHeight() - p.footerSpace() - p.footnoteSpace
+	sepY := y + p.footnotes[0].lineHeight/2
+	p.Line(left, sepY, left+(p.GetPageWidth()-left-right)/3, sepY, p.themeColour(p.theme.Footnote.Colour), p.mm(0.2))
+	y += p.footnotes[0].lineHeight
+
+	p.useTextStyle(p.theme.Footnote)
+	for _, note := range p.footnotes {
+		if note.marker != "" {
+			p.pdf.SetXY(left, y)
+			p.pdf.SubWrite(note.lineHeight, note.marker, p.font.Size*0.8, p.font.Size*0.3, 0, "")
+		}
+		for _, line := range note.lines {
+			p.pdf.SetXY(left+note.indent, y)
+			p.pdf.CellFormat(0, note.lineHeight, line, "", 0, "L", false, 0, "")
+			y += note.lineHeight
+		}
+	}
+
+	// set the font and foreground back to how they were
+	p.SetFont(currentFont)
+	p.SetLineHeight(currentLH)
+	p.SetForeground(currentFG)
+	p.pdf.SetXY(currentX, currentY)
+
+	p.footnotes, p.footnoteSpace = nil, 0
+
+	p.checkpoint("Footnotes printed")
+}
@@ -0,0 +1,60 @@
+package pdfb
+
+// the number of times Generate builds a document before giving up on
+// settling its layout
+const maxGeneratePasses = 5
+
+// Generate is used to build a document whose table of contents and
+// references depend on the pages that come after them
+// The document is built by calling build, and is built again while the ToC
+// or a list of figures or tables doesn't take up exactly the pages set aside
+// for it, or references were written without their final text. Each pass
+// uses the layout of the last, so build should write the same content each
+// time it is called. Documents without listings or forward references are
+// only built once.
+// The document returned is complete and ready to be output.
+func Generate(build func(p *Pdfb), options ...Option) (*Pdfb, error) {
+	var p *Pdfb
+	var listingPages []int
+	var anchors map[string]anchor
+
+	for pass := 1; pass <= maxGeneratePasses; pass++ {
+		p = New(append(options, withLayout(listingPages, anchors))...)
+		build(p)
+		if p.Err() {
+			return p, p.Error()
+		}
+
+		p.generating = true
+		p.finalFunc()
+		p.generating = false
+		if p.Err() {
+			return p, p.Error()
+		}
+		if p.settled {
+			p.finalised = true
+			p.logf(LogDebug, "Document generated in %d passes", pass)
+			return p, nil
+		}
+
+		// the next pass uses the layout of this one
+		listingPages = nil
+		for _, l := range p.listings {
+			listingPages = append(listingPages, l.pagesNeeded)
+		}
+		anchors = p.placedAnchors()
+	}
+
+	p.SetErrorf("%w after %d passes", ErrLayoutNotSettled, maxGeneratePasses)
+	return p, p.Error()
+}
+
+// used to set the layout of a document from the last pass of Generate,
+// the number of pages set aside for the ToC and other listings and the
+// anchors to expect
+func withLayout(listingPages []int, anchors map[string]anchor) Option {
+	return func(p *Pdfb) {
+		p.listingPages = listingPages
+		p.anchorEstimates = anchors
+	}
+}
@@ -4,6 +4,8 @@ go 1.15
 
 require (
 	github.com/barjoio/utils v0.0.0-20201202183825-8ca1e28b3f76
+	github.com/disintegration/gift v1.2.1
 	github.com/fatih/color v1.10.0 // indirect
 	github.com/jung-kurt/gofpdf v1.16.2
+	github.com/yuin/goldmark v1.4.12
 )
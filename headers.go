@@ -0,0 +1,466 @@
+package pdfb
+
+import "strings"
+
+// the height of the header and footer when none has been set, in millimetres
+const defaultHeaderHeight = 25.0
+
+// PageContext describes the page that a header or footer is drawn on
+// Page counts every page of the document, and Label is the number of the
+// page as it is written, set using SetPageNumbering. Pages and SectionPages
+// are aliases that are replaced with the number of pages in the document and
+// in the page numbering section when the document is output. Left and Right
+// are the margins of the page. Heading is the text of the first level 1
+// heading on the page, or the last one before the page if there are none on
+// it. Chapter and Section are the text of the last level 1 and level 2
+// headings on the page or before it, a section belongs to the chapter it
+// comes after. ChapterStart is set on pages with a level 1 heading.
+type PageContext struct {
+	Page         int
+	Label        string
+	Pages        string
+	SectionPages string
+	Width        float64
+	Height       float64
+	Left         float64
+	Right        float64
+	Heading      string
+	Chapter      string
+	Section      string
+	First        bool
+	Odd          bool
+	Even         bool
+	ChapterStart bool
+}
+
+// PageFunc is used to draw a header or footer
+type PageFunc func(p *Pdfb, ctx PageContext)
+
+// pageKind is used to define the kinds of page that can have their own
+// header or footer
+type pageKind int
+
+const (
+	bodyPage pageKind = iota
+	firstPage
+	evenPage
+	chapterPage
+)
+
+// pageLayout is used to define the header or footer drawn on each kind of
+// page, a kind of page that has been set without a function is left blank
+type pageLayout struct {
+	from  int
+	funcs map[pageKind]PageFunc
+}
+
+// used to get the function that draws the header or footer of a page,
+// which is nil if there isn't one
+// The first page comes before pages that start a chapter, which come
+// before even pages.
+func (l *pageLayout) forPage(ctx PageContext) PageFunc {
+	if ctx.Page < l.from {
+		return nil
+	}
+	kinds := []struct {
+		kind pageKind
+		is   bool
+	}{
+		{firstPage, ctx.First},
+		{chapterPage, ctx.ChapterStart},
+		{evenPage, ctx.Even},
+	}
+	for _, k := range kinds {
+		if fn, ok := l.funcs[k.kind]; ok && k.is {
+			return fn
+		}
+	}
+	return l.funcs[bodyPage]
+}
+
+// used to set the function that draws the header of a kind of page, space
+// is only set aside for the header once there is one to draw
+func (p *Pdfb) setHeaderFunc(kind pageKind, fn PageFunc) {
+	if fn != nil && p.headerHeight == 0 {
+		p.headerHeight = p.mm(defaultHeaderHeight)
+	}
+	if p.header.funcs == nil {
+		p.header.funcs = map[pageKind]PageFunc{}
+	}
+	p.header.funcs[kind] = fn
+}
+
+// used to set the function that draws the footer of a kind of page, space
+// is only set aside for the footer once there is one to draw
+func (p *Pdfb) setFooterFunc(kind pageKind, fn PageFunc) {
+	if fn != nil && p.footerHeight == 0 {
+		p.footerHeight = p.mm(defaultHeaderHeight)
+	}
+	if p.footer.funcs == nil {
+		p.footer.funcs = map[pageKind]PageFunc{}
+	}
+	p.footer.funcs[kind] = fn
+
+	// set the space from the bottom where the auto page break gets triggered
+	p.setPageBreak()
+}
+
+// SetHeaderFunc is used to set a function that draws the header of each page
+// The header is drawn from the next page onward, once the content of the page
+// has been written, and content starts under it. The function can draw
+// anything, but shouldn't add pages. The cursor, font and colours are put
+// back afterwards.
+func (p *Pdfb) SetHeaderFunc(fn PageFunc) {
+	p.setHeaderFunc(bodyPage, fn)
+	p.header.from = p.pdf.PageNo() + 1
+	p.checkpoint("Header set")
+}
+
+// SetFooterFunc is used to set a function that draws the footer of each page
+// The footer is drawn from the next page onward, once the content of the page
+// has been written, and content stops above it. The function can draw
+// anything, but shouldn't add pages. The cursor, font and colours are put
+// back afterwards.
+func (p *Pdfb) SetFooterFunc(fn PageFunc) {
+	p.setFooterFunc(bodyPage, fn)
+	p.footer.from = p.pdf.PageNo() + 1
+	p.checkpoint("Footer set")
+}
+
+// SetHeader is used to set the header
+// The page number and number of pages can be used in the header
+// using {page} and {pages}, and the current chapter and section
+// using {chapter} and {section}.
+// Text aligned "outer" or "inner" goes on the outside or inside edge of
+// the page, which swap between odd and even pages.
+func (p *Pdfb) SetHeader(fontFamily string, content ...TextAlign) {
+	p.SetHeaderFunc(p.textHeader(fontFamily, content))
+}
+
+// SetFooter is used to set the footer
+// The page number and number of pages can be used in the footer
+// using {page} and {pages}, and the current chapter and section
+// using {chapter} and {section}.
+// Text aligned "outer" or "inner" goes on the outside or inside edge of
+// the page, which swap between odd and even pages.
+//
+// Eg. "Page {page} of {pages}"
+func (p *Pdfb) SetFooter(fontFamily string, content ...TextAlign) {
+	p.SetFooterFunc(p.textFooter(fontFamily, content))
+}
+
+// SetFirstHeaderFunc is used to set a function that draws the header of the
+// first page, in place of the header set by SetHeaderFunc
+// A nil function leaves the header of the first page blank, eg. for a cover.
+func (p *Pdfb) SetFirstHeaderFunc(fn PageFunc) {
+	p.setHeaderFunc(firstPage, fn)
+	p.checkpoint("First page header set")
+}
+
+// SetFirstFooterFunc is used to set a function that draws the footer of the
+// first page, in place of the footer set by SetFooterFunc
+// A nil function leaves the footer of the first page blank, eg. for a cover.
+func (p *Pdfb) SetFirstFooterFunc(fn PageFunc) {
+	p.setFooterFunc(firstPage, fn)
+	p.checkpoint("First page footer set")
+}
+
+// SetEvenHeaderFunc is used to set a function that draws the header of even
+// pages, in place of the header set by SetHeaderFunc which is then only drawn
+// on odd pages
+// A nil function leaves the header of even pages blank.
+func (p *Pdfb) SetEvenHeaderFunc(fn PageFunc) {
+	p.setHeaderFunc(evenPage, fn)
+	p.checkpoint("Even page header set")
+}
+
+// SetEvenFooterFunc is used to set a function that draws the footer of even
+// pages, in place of the footer set by SetFooterFunc which is then only drawn
+// on odd pages
+// A nil function leaves the footer of even pages blank.
+func (p *Pdfb) SetEvenFooterFunc(fn PageFunc) {
+	p.setFooterFunc(evenPage, fn)
+	p.checkpoint("Even page footer set")
+}
+
+// SetChapterHeaderFunc is used to set a function that draws the header of
+// pages that start a chapter, in place of the header set by SetHeaderFunc
+// A nil function leaves the header of those pages blank.
+func (p *Pdfb) SetChapterHeaderFunc(fn PageFunc) {
+	p.setHeaderFunc(chapterPage, fn)
+	p.checkpoint("Chapter header set")
+}
+
+// SetChapterFooterFunc is used to set a function that draws the footer of
+// pages that start a chapter, in place of the footer set by SetFooterFunc
+// A nil function leaves the footer of those pages blank.
+func (p *Pdfb) SetChapterFooterFunc(fn PageFunc) {
+	p.setFooterFunc(chapterPage, fn)
+	p.checkpoint("Chapter footer set")
+}
+
+// SetFirstHeader is used to set the header of the first page, the same
+// way as SetHeader
+// With no content, the header of the first page is left blank.
+func (p *Pdfb) SetFirstHeader(fontFamily string, content ...TextAlign) {
+	p.SetFirstHeaderFunc(p.textHeader(fontFamily, content))
+}
+
+// SetFirstFooter is used to set the footer of the first page, the same
+// way as SetFooter
+// With no content, the footer of the first page is left blank.
+func (p *Pdfb) SetFirstFooter(fontFamily string, content ...TextAlign) {
+	p.SetFirstFooterFunc(p.textFooter(fontFamily, content))
+}
+
+// SetEvenHeader is used to set the header of even pages, the same way
+// as SetHeader
+// With no content, the header of even pages is left blank.
+func (p *Pdfb) SetEvenHeader(fontFamily string, content ...TextAlign) {
+	p.SetEvenHeaderFunc(p.textHeader(fontFamily, content))
+}
+
+// SetEvenFooter is used to set the footer of even pages, the same way
+// as SetFooter
+// With no content, the footer of even pages is left blank.
+func (p *Pdfb) SetEvenFooter(fontFamily string, content ...TextAlign) {
+	p.SetEvenFooterFunc(p.textFooter(fontFamily, content))
+}
+
+// SetChapterHeader is used to set the header of pages that start a chapter,
+// the same way as SetHeader
+// With no content, the header of those pages is left blank.
+func (p *Pdfb) SetChapterHeader(fontFamily string, content ...TextAlign) {
+	p.SetChapterHeaderFunc(p.textHeader(fontFamily, content))
+}
+
+// SetChapterFooter is used to set the footer of pages that start a chapter,
+// the same way as SetFooter
+// With no content, the footer of those pages is left blank.
+func (p *Pdfb) SetChapterFooter(fontFamily string, content ...TextAlign) {
+	p.SetChapterFooterFunc(p.textFooter(fontFamily, content))
+}
+
+// used to make a function that prints a header of text sections, there
+// is no function without any sections
+func (p *Pdfb) textHeader(fontFamily string, content []TextAlign) PageFunc {
+	if len(content) == 0 {
+		return nil
+	}
+	return func(p *Pdfb, ctx PageContext) {
+		// set font and foreground for header text, the font family
+		// given overrides the theme
+		style := p.theme.Header
+		if fontFamily != "" {
+			style.Font.Family = fontFamily
+		}
+		p.useTextStyle(style)
+
+		p.textAlignRow(ctx, 0, p.headerHeight, content)
+	}
+}
+
+// used to make a function that prints a footer of text sections, there
+// is no function without any sections
+func (p *Pdfb) textFooter(fontFamily string, content []TextAlign) PageFunc {
+	if len(content) == 0 {
+		return nil
+	}
+	return func(p *Pdfb, ctx PageContext) {
+		// set font and foreground for footer text, the font family
+		// given overrides the theme
+		style := p.theme.Footer
+		if fontFamily != "" {
+			style.Font.Family = fontFamily
+		}
+		p.useTextStyle(style)
+
+		p.textAlignRow(ctx, ctx.Height-p.footerHeight, p.footerHeight, content)
+	}
+}
+
+// SetHeaderHeight is used to set the height of the header
+func (p *Pdfb) SetHeaderHeight(headerHeight float64) {
+	p.headerHeight = headerHeight
+}
+
+// GetHeaderHeight is used to get the height of the header
+func (p *Pdfb) GetHeaderHeight() float64 {
+	return p.headerHeight
+}
+
+// SetFooterHeight is used to set the height of the footer
+func (p *Pdfb) SetFooterHeight(footerHeight float64) {
+	p.footerHeight = footerHeight
+	p.setPageBreak()
+}
+
+// GetFooterHeight is used to get the height of the footer
+func (p *Pdfb) GetFooterHeight() float64 {
+	return p.footerHeight
+}
+
+// used to print a row of text sections across the page, each section
+// gets an equal share of the width between the margins
+func (p *Pdfb) textAlignRow(ctx PageContext, y, h float64, content []TextAlign) {
+	if len(content) == 0 {
+		return
+	}
+	sectionWidth := (ctx.Width - ctx.Left - ctx.Right) / float64(len(content))
+
+	p.pdf.SetXY(ctx.Left, y)
+	for _, c := range content {
+		text := strings.NewReplacer(
+			"{page}", ctx.Label,
+			"{sectionpages}", ctx.SectionPages,
+			"{chapter}", ctx.Chapter,
+			"{section}", ctx.Section,
+		).Replace(c.Text)
+		align := p.makeAlignStr(pageSideAlign(c.Align, ctx.Odd))
+
+		// the aliases of the number of pages are wider than the numbers
+		// that replace them, so the text is moved over by the difference,
+		// taking the number of pages to be as wide as the page number
+		var offset float64
+		for _, alias := range []string{ctx.Pages, ctx.SectionPages} {
+			if n := strings.Count(text, alias); n > 0 {
+				offset += float64(n) * (p.pdf.GetStringWidth(alias) - p.pdf.GetStringWidth(ctx.Label))
+			}
+		}
+		x := p.GetX()
+		switch align {
+		case "C":
+			p.SetX(x + offset/2)
+		case "R":
+			p.SetX(x + offset)
+		}
+		p.pdf.CellFormat(sectionWidth, h, text, "", 0, "M"+align, false, 0, "")
+		p.SetX(x + sectionWidth)
+	}
+}
+
+// used to turn "outer" and "inner" alignment into left or right alignment,
+// odd pages are on the right of a spread so their outer edge is the right
+func pageSideAlign(align string, odd bool) string {
+	switch strings.ToLower(align) {
+	case "outer":
+		if odd {
+			return "right"
+		}
+		return "left"
+	case "inner":
+		if odd {
+			return "left"
+		}
+		return "right"
+	}
+	return align
+}
+
+// used to get the context of the current page
+func (p *Pdfb) pageContext() PageContext {
+	page := p.pdf.PageNo()
+	w, h := p.currentPageSize()
+	ctx := PageContext{
+		Page:         page,
+		Label:        p.pageLabel(page),
+		Pages:        "{pages}",
+		SectionPages: p.sectionPagesAlias(page),
+		Width:        w,
+		Height:       h,
+		Heading:      p.pageHeading(page),
+		First:        page == 1,
+		Odd:          page%2 == 1,
+		Even:         page%2 == 0,
+	}
+	ctx.Left, ctx.Right = p.sideMargins(page)
+	ctx.Chapter, ctx.Section, ctx.ChapterStart = p.runningHeadings(page)
+	return ctx
+}
+
+// used to get the text of the last level 1 and level 2 headings on a page
+// or before it, and whether a level 1 heading is on the page
+func (p *Pdfb) runningHeadings(page int) (chapter, section string, start bool) {
+	for _, h := range p.headings {
+		if h.page > page {
+			continue
+		}
+		switch h.level {
+		case 1:
+			chapter, section = h.text, ""
+			start = start || h.page == page
+		case 2:
+			section = h.text
+		}
+	}
+	return
+}
+
+// used to get the text of the first level 1 heading on a page, or the last
+// one before the page
+func (p *Pdfb) pageHeading(page int) (text string) {
+	for _, h := range p.headings {
+		if h.level != 1 {
+			continue
+		}
+		if h.page > page {
+			break
+		}
+		text = h.text
+		if h.page == page {
+			break
+		}
+	}
+	return
+}
+
+// used at the start of a page to move the cursor under the header
+func (p *Pdfb) startHeader() {
+	if p.pdf.PageNo() >= p.header.from && p.headerHeight > 0 {
+		p.pdf.SetY(p.topSpace())
+	}
+}
+
+// used at the end of a page to draw the header and footer, the state of
+// the document is put back afterwards
+func (p *Pdfb) printHeaderFooter() {
+	ctx := p.pageContext()
+	header := p.header.forPage(ctx)
+	footer := p.footer.forPage(ctx)
+	if header == nil && footer == nil {
+		return
+	}
+
+	// copy the current state
+	currentFont := p.fontCopy(p.font)
+	currentLH := p.lineHeight
+	currentFG := p.foreground
+	currentX, currentY := p.GetX(), p.GetY()
+	drawR, drawG, drawB := p.pdf.GetDrawColor()
+	fillR, fillG, fillB := p.pdf.GetFillColor()
+	lineWidth := p.pdf.GetLineWidth()
+	left, top, right, _ := p.pdf.GetMargins()
+
+	// headers and footers are drawn between the page margins
+	p.pdf.SetLeftMargin(ctx.Left)
+	p.pdf.SetRightMargin(ctx.Right)
+
+	if header != nil {
+		header(p, ctx)
+		p.checkpoint("Header printed")
+	}
+	if footer != nil {
+		footer(p, ctx)
+		p.checkpoint("Footer printed")
+	}
+
+	// put the state back
+	p.pdf.SetMargins(left, top, right)
+	p.pdf.SetLineWidth(lineWidth)
+	p.pdf.SetFillColor(fillR, fillG, fillB)
+	p.pdf.SetDrawColor(drawR, drawG, drawB)
+	p.SetFont(currentFont)
+	p.SetLineHeight(currentLH)
+	p.SetForeground(currentFG)
+	p.pdf.SetXY(currentX, currentY)
+}
@@ -0,0 +1,179 @@
+package pdfb
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+
+	// decoders for the formats supported by gofpdf
+	_ "image/gif"
+	_ "image/jpeg"
+
+	"github.com/disintegration/gift"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// ImageOptions defines extra options for inserting an image
+// Filters are applied to the image in order before it is inserted, see
+// github.com/disintegration/gift for the available filters. A Caption is
+// numbered and written under the image, and is listed by ListOfFigures.
+// eg. ImageOptions{Filters: []gift.Filter{gift.Grayscale(), gift.GaussianBlur(1)}}
+type ImageOptions struct {
+	Filters []gift.Filter
+	Caption string
+}
+
+// ImageFromReader is used to insert an image read from r
+// format is the image type (jpg, png or gif), and key is the name the image
+// is registered under, so an image used more than once is only read once
+// Use 0 in place of w or h to keep the aspect ratio
+func (p *Pdfb) ImageFromReader(key, format string, r io.Reader, align string, x, y, w, h float64, options ...ImageOptions) {
+	opts := mergeImageOptions(options)
+
+	name := key
+	if len(opts.Filters) > 0 {
+		name = p.registerFiltered(key, func() (image.Image, error) {
+			img, _, err := image.Decode(r)
+			return img, err
+		}, opts.Filters)
+	} else if p.pdf.GetImageInfo(key) == nil {
+		p.pdf.RegisterImageOptionsReader(key, gofpdf.ImageOptions{ImageType: format}, r)
+	}
+	if p.Err() {
+		return
+	}
+
+	p.placeImage(name, align, x, y, w, h, opts.Caption)
+
+	p.checkpoint("Image printed")
+}
+
+// ImageFromBytes is used to insert an image from its encoded bytes
+// See ImageFromReader for the meaning of format and key
+func (p *Pdfb) ImageFromBytes(key, format string, b []byte, align string, x, y, w, h float64, options ...ImageOptions) {
+	p.ImageFromReader(key, format, bytes.NewReader(b), align, x, y, w, h, options...)
+}
+
+// ImageFromGo is used to insert an image.Image, such as one drawn by Go code
+// key is the name the image is registered under, so an image used more than
+// once is only encoded once
+// Use 0 in place of w or h to keep the aspect ratio
+func (p *Pdfb) ImageFromGo(key string, img image.Image, align string, x, y, w, h float64, options ...ImageOptions) {
+	opts := mergeImageOptions(options)
+
+	name := key
+	if len(opts.Filters) > 0 {
+		name = p.registerFiltered(key, func() (image.Image, error) {
+			return img, nil
+		}, opts.Filters)
+	} else if p.pdf.GetImageInfo(key) == nil {
+		// gofpdf can't use an image.Image directly, so it is encoded as a png
+		buf := new(bytes.Buffer)
+		if err := png.Encode(buf, img); err != nil {
+			p.SetErrorf("encoding image %s: %w", key, err)
+			return
+		}
+		p.pdf.RegisterImageOptionsReader(key, gofpdf.ImageOptions{ImageType: "PNG"}, buf)
+	}
+	if p.Err() {
+		return
+	}
+
+	p.placeImage(name, align, x, y, w, h, opts.Caption)
+
+	p.checkpoint("Image printed")
+}
+
+// used to combine the options passed to the image functions
+func mergeImageOptions(options []ImageOptions) (merged ImageOptions) {
+	for _, o := range options {
+		merged.Filters = append(merged.Filters, o.Filters...)
+		if o.Caption != "" {
+			merged.Caption = o.Caption
+		}
+	}
+	return
+}
+
+// used to apply filters to an image in memory and register the result with
+// the pdf, the result is registered under the source name plus a hash of the
+// filtered image, so that the same source with different filters doesn't
+// collide, the name that the image was registered under is returned
+func (p *Pdfb) registerFiltered(name string, decode func() (image.Image, error), filters []gift.Filter) string {
+	src, err := decode()
+	if err != nil {
+		p.SetErrorf("decoding image %s: %w", name, err)
+		return ""
+	}
+
+	g := gift.New(filters...)
+	dst := image.NewNRGBA(g.Bounds(src.Bounds()))
+	g.Draw(dst, src)
+
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, dst); err != nil {
+		p.SetErrorf("encoding image %s: %w", name, err)
+		return ""
+	}
+
+	sum := sha1.Sum(buf.Bytes())
+	key := name + "#" + hex.EncodeToString(sum[:8])
+	if p.pdf.GetImageInfo(key) == nil {
+		p.pdf.RegisterImageOptionsReader(key, gofpdf.ImageOptions{ImageType: "PNG"}, buf)
+	}
+
+	p.checkpoint("Image filtered")
+	return key
+}
+
+// used to draw a registered image, w or h are calculated from the aspect
+// ratio when 0, and x is calculated for centre or right alignment, the
+// caption is numbered and written under the image
+func (p *Pdfb) placeImage(name, align string, x, y, w, h float64, caption string) {
+	info := p.pdf.GetImageInfo(name)
+	if info == nil {
+		return
+	}
+
+	// calc w and/or h values if 0 is given
+	if w == 0 {
+		w = h * info.Width() / info.Height()
+	}
+	if h == 0 {
+		h = w * info.Height() / info.Width()
+	}
+
+	// keep the caption on the same page as the image
+	if caption != "" {
+		caption = captionText("Figure", len(p.figures)+1, caption)
+		if p.GetY()+h+p.captionHeight(caption) > p.GetPageHeight()-p.bottomSpace() {
+			p.pageBreak()
+		}
+		p.addCaption(&p.figures, caption)
+	}
+
+	// align image for left, right, or centre
+	left, right := p.contentEdges()
+	align = strings.ToLower(align)
+	switch {
+	case align == "l" || align == "left" || align == "":
+	case align == "c" || align == "centre":
+		x = left + (right-left)/2 - w/2
+	case align == "r" || align == "right":
+		x = right - w
+	default:
+		p.SetErrorf("%w: %s", ErrInvalidAlign, align)
+		return
+	}
+
+	// draw image
+	p.pdf.ImageOptions(name, x, y, w, h, true, gofpdf.ImageOptions{}, 0, "")
+
+	if caption != "" {
+		p.writeCaption(caption)
+	}
+}
@@ -0,0 +1,194 @@
+package pdfb
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// indexTerm is used to define a place in the document that a term of
+// the index refers to
+type indexTerm struct {
+	term    string
+	subterm string
+	page    int
+	link    int
+}
+
+// IndexOptions defines how the index is written
+// Columns defaults to 2, and Gutter to twice the indent size
+type IndexOptions struct {
+	Columns int
+	Gutter  float64
+}
+
+// IndexTerm is used to add the current page to the index under a term,
+// use an empty subterm to add the page to the term itself
+func (p *Pdfb) IndexTerm(term, subterm string) {
+	if term == "" {
+		return
+	}
+
+	// link to the position of the term, the same way headings are linked
+	link := p.pdf.AddLink()
+	p.pdf.SetLink(link, p.GetY(), p.pdf.PageNo())
+
+	p.indexTerms = append(p.indexTerms, indexTerm{term, subterm, p.pdf.PageNo(), link})
+
+	p.checkpoint("Index term added")
+}
+
+// Index is used to write an index of the terms added with IndexTerm so far
+// Terms are sorted alphabetically and grouped by their first letter, which
+// is written in the index group style of the theme, with their subterms
+// listed under them. Runs of pages are written as ranges,
+// and each page number links to the term on that page.
+func (p *Pdfb) Index(options ...IndexOptions) {
+	var opts IndexOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.Columns == 0 {
+		opts.Columns = 2
+	}
+	if opts.Gutter == 0 {
+		opts.Gutter = p.indentSize * 2
+	}
+
+	// copy current font, foreground and columns
+	currentFont := p.fontCopy(p.font)
+	currentFG := p.foreground
+	currentColumns, currentGutter := p.GetColumns()
+
+	// sort the terms, then subterms, keeping the order they were added in
+	terms := append([]indexTerm(nil), p.indexTerms...)
+	sort.SliceStable(terms, func(i, j int) bool {
+		a, b := strings.ToLower(terms[i].term), strings.ToLower(terms[j].term)
+		if a != b {
+			return a < b
+		}
+		return strings.ToLower(terms[i].subterm) < strings.ToLower(terms[j].subterm)
+	})
+
+	p.SetColumns(opts.Columns, opts.Gutter)
+
+	var group string
+	for i := 0; i < len(terms); {
+		// all the places a term and subterm are found
+		j := i
+		for j < len(terms) && strings.EqualFold(terms[j].term, terms[i].term) &&
+			strings.EqualFold(terms[j].subterm, terms[i].subterm) {
+			j++
+		}
+		entry := terms[i:j]
+
+		// start a new group when the first letter changes, the letter is
+		// kept with the first entry of the group
+		if g := indexGroup(entry[0].term); g != group {
+			group = g
+			if p.GetY()+p.lineHeight*3 > p.GetPageHeight()-p.bottomSpace() {
+				p.pageBreak()
+			} else if i > 0 {
+				p.Ln(1)
+			}
+			p.useTextStyle(p.theme.IndexGroup)
+			p.WriteLn("%s", group)
+			p.SetFont(currentFont)
+			p.SetForeground(currentFG)
+		}
+
+		// a subterm without an entry for its term still needs the term
+		// written above it
+		if entry[0].subterm != "" && (i == 0 || !strings.EqualFold(terms[i-1].term, entry[0].term)) {
+			p.WriteLn("%s", entry[0].term)
+		}
+
+		p.indexEntry(entry)
+		i = j
+	}
+
+	// go back to the columns from before the index
+	p.SetColumns(currentColumns, currentGutter)
+
+	p.checkpoint("Index printed")
+}
+
+// used to write a term or subterm of the index followed by its pages,
+// subterms are indented and wrapped lines are indented further
+func (p *Pdfb) indexEntry(entry []indexTerm) {
+	left, _ := p.contentEdges()
+	text, indent := entry[0].term, 0.0
+	if entry[0].subterm != "" {
+		text, indent = entry[0].subterm, p.indentSize
+	}
+
+	// wrapped lines start further in than the first line
+	p.pdf.SetLeftMargin(left + indent + p.indentSize)
+	p.SetX(left + indent)
+	p.pdf.Write(p.lineHeight, text)
+
+	for _, r := range p.indexPageRanges(entry) {
+		p.pdf.Write(p.lineHeight, ", ")
+		p.pdf.WriteLinkID(p.lineHeight, r.text, r.link)
+	}
+
+	// set the margin back to the edge of the column, which may have
+	// changed while writing
+	if p.columns > 1 {
+		p.setColumnMargins()
+	} else {
+		margin, _ := p.sideMargins(p.pdf.PageNo())
+		p.pdf.SetLeftMargin(margin)
+	}
+	p.Ln(1)
+}
+
+// indexPageRange is used to define a page or run of pages in the index
+type indexPageRange struct {
+	text string
+	link int
+}
+
+// used to collapse the pages of an entry into ranges, eg. 3, 4, 5 and 9
+// become 3-5 and 9, each range links to the first place on its first page
+// A range doesn't run across pages that are numbered separately.
+func (p *Pdfb) indexPageRanges(entry []indexTerm) (ranges []indexPageRange) {
+	// the first link of each page
+	links := map[int]int{}
+	var pages []int
+	for _, t := range entry {
+		if _, ok := links[t.page]; !ok {
+			links[t.page] = t.link
+			pages = append(pages, t.page)
+		}
+	}
+	sort.Ints(pages)
+
+	for i := 0; i < len(pages); {
+		j := i
+		for j+1 < len(pages) && pages[j+1] == pages[j]+1 &&
+			p.pageSectionIndex(pages[j+1]) == p.pageSectionIndex(pages[i]) {
+			j++
+		}
+		text := p.pageLabel(pages[i])
+		if j > i {
+			text += "-" + p.pageLabel(pages[j])
+		}
+		ranges = append(ranges, indexPageRange{text, links[pages[i]]})
+		i = j + 1
+	}
+
+	return
+}
+
+// used to get the group of a term in the index, which is its first
+// letter, terms that don't start with a letter are grouped under #
+func indexGroup(term string) string {
+	for _, r := range term {
+		if unicode.IsLetter(r) {
+			return string(unicode.ToUpper(r))
+		}
+		return "#"
+	}
+	return "#"
+}
@@ -0,0 +1,110 @@
+package pdfb
+
+import (
+	"fmt"
+	"io"
+)
+
+// LogLevel defines the severity of a log message
+type LogLevel int
+
+// Log levels, from the most verbose to the least verbose
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogError
+)
+
+// String is used to get the name of the level
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+// Logger is used to receive messages about what the builder is doing
+// Debug messages trace every builder operation, so they are best
+// reserved for debugging
+type Logger interface {
+	Log(level LogLevel, msg string)
+}
+
+// LevelLogger is a Logger that reports which levels it logs, messages at
+// other levels are not formatted or sent to it
+type LevelLogger interface {
+	Logger
+	Enabled(level LogLevel) bool
+}
+
+// LoggerFunc allows an ordinary function to be used as a Logger
+type LoggerFunc func(level LogLevel, msg string)
+
+// Log calls f(level, msg)
+func (f LoggerFunc) Log(level LogLevel, msg string) {
+	f(level, msg)
+}
+
+// NewLogger returns a Logger which writes messages at or above
+// the given level to w, one per line
+func NewLogger(w io.Writer, level LogLevel) Logger {
+	return &writerLogger{w: w, level: level}
+}
+
+// writerLogger is the Logger returned by NewLogger
+type writerLogger struct {
+	w     io.Writer
+	level LogLevel
+}
+
+func (l *writerLogger) Log(level LogLevel, msg string) {
+	if !l.Enabled(level) {
+		return
+	}
+	fmt.Fprintf(l.w, "pdfb %s: %s\n", level, msg)
+}
+
+func (l *writerLogger) Enabled(level LogLevel) bool {
+	return level >= l.level
+}
+
+// nopLogger discards every message, it is the default Logger
+type nopLogger struct{}
+
+func (nopLogger) Log(LogLevel, string) {}
+
+func (nopLogger) Enabled(LogLevel) bool { return false }
+
+// WithLogger is used to set the Logger when creating a document
+func WithLogger(logger Logger) Option {
+	return func(p *Pdfb) {
+		p.SetLogger(logger)
+	}
+}
+
+// SetLogger is used to set the logger, nil silences logging
+func (p *Pdfb) SetLogger(logger Logger) {
+	if logger == nil {
+		logger = nopLogger{}
+	}
+	p.logger = logger
+}
+
+// GetLogger is used to get the logger
+func (p *Pdfb) GetLogger() Logger {
+	return p.logger
+}
+
+// used to send a message to the logger, the message is only
+// formatted when the logger logs messages at the level
+func (p *Pdfb) logf(level LogLevel, format string, a ...interface{}) {
+	if l, ok := p.logger.(LevelLogger); ok && !l.Enabled(level) {
+		return
+	}
+	p.logger.Log(level, fmt.Sprintf(format, a...))
+}
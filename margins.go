@@ -0,0 +1,69 @@
+package pdfb
+
+// Margins defines the space between the edges of a page and its content
+// Gutter is extra space on the inside edge of the page, where it is bound.
+// When Mirrored, Left and Right are the inside and outside margins of odd
+// pages and swap over on even pages, so that facing pages mirror each other,
+// otherwise the gutter is on the left of every page.
+type Margins struct {
+	Top      float64
+	Bottom   float64
+	Left     float64
+	Right    float64
+	Gutter   float64
+	Mirrored bool
+}
+
+// SetMargins is used to set each of the margins
+func (p *Pdfb) SetMargins(margins Margins) {
+	p.margins = margins
+
+	left, right := p.sideMargins(p.pdf.PageNo())
+	p.pdf.SetMargins(left, margins.Top, right)
+	if p.columns > 1 {
+		p.setColumnMargins()
+	}
+	p.setPageBreak()
+
+	p.checkpoint("Margins set")
+}
+
+// GetMargins is used to get each of the margins
+func (p *Pdfb) GetMargins() Margins {
+	return p.margins
+}
+
+// used to get the left and right margins of a page, including the gutter
+func (p *Pdfb) sideMargins(page int) (left, right float64) {
+	m := p.margins
+	left, right = m.Left+m.Gutter, m.Right
+	if m.Mirrored && page > 1 && page%2 == 0 {
+		left, right = m.Right, m.Left+m.Gutter
+	}
+	return
+}
+
+// used at the start of a page to move the margins of the pdf from those of
+// the last page to those of the new page, which differ when margins are
+// mirrored, any indent from the margins is kept
+func (p *Pdfb) startMargins() {
+	page := p.pdf.PageNo()
+	lastLeft, lastRight := p.sideMargins(page - 1)
+	left, right := p.sideMargins(page)
+	if left == lastLeft && right == lastRight {
+		return
+	}
+
+	pdfLeft, top, pdfRight, _ := p.pdf.GetMargins()
+	p.pdf.SetMargins(pdfLeft+left-lastLeft, top, pdfRight+right-lastRight)
+	p.pdf.SetX(p.pdf.GetX() + left - lastLeft)
+}
+
+// used before the pdf breaks onto a new page, to move the cursor by the
+// change in the left margin, as the pdf keeps x across the page break
+func (p *Pdfb) keepXAcrossPages() {
+	page := p.pdf.PageNo()
+	left, _ := p.sideMargins(page)
+	nextLeft, _ := p.sideMargins(page + 1)
+	p.pdf.SetX(p.pdf.GetX() + nextLeft - left)
+}
@@ -0,0 +1,246 @@
+package pdfb
+
+import (
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Markdown is used to write a markdown document
+// Headings are written using Heading, so they appear in the ToC and bookmarks,
+// paragraphs using Paragraph, or RichText when they contain emphasis, code or
+// links, lists using List or OrderedList, with the same styling of their
+// items, and images using Image. Code spans
+// and code blocks are written in the code style of the theme.
+func (p *Pdfb) Markdown(src string) {
+	source := []byte(src)
+	doc := goldmark.DefaultParser().Parse(text.NewReader(source))
+
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		p.markdownBlock(n, source)
+	}
+
+	p.checkpoint("Markdown printed")
+}
+
+// used to write a markdown block node
+func (p *Pdfb) markdownBlock(n ast.Node, source []byte) {
+	switch n := n.(type) {
+	case *ast.Heading:
+		p.Heading(n.Level, markdownText(n, source))
+
+	case *ast.Paragraph, *ast.TextBlock:
+		// a paragraph of plain text is a regular paragraph
+		if isPlainMarkdown(n) {
+			p.Paragraph("%s", markdownText(n, source))
+			return
+		}
+		p.markdownParagraph(n, source)
+		p.Ln(1)
+
+	case *ast.List:
+		items := []ListItem{}
+		p.markdownListItems(n, source, 1, &items)
+		if n.IsOrdered() {
+			p.OrderedList(items, ListOptions{Start: n.Start})
+		} else {
+			p.List(items)
+		}
+		p.Ln(1)
+
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		currentFont := p.fontCopy(p.font)
+		currentFG := p.foreground
+		p.useTextStyle(p.theme.Code)
+		lines := n.Lines()
+		for i := 0; i < lines.Len(); i++ {
+			line := lines.At(i)
+			p.WriteLn("%s", strings.TrimRight(string(line.Value(source)), "\r\n"))
+		}
+		p.SetFont(currentFont)
+		p.SetForeground(currentFG)
+		p.Ln(1)
+
+	case *ast.Blockquote:
+		currentFont := p.fontCopy(p.font)
+		p.font.Italic = true
+		p.SetFont(p.font)
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			p.markdownBlock(c, source)
+		}
+		p.SetFont(currentFont)
+
+	case *ast.ThematicBreak:
+		left, right := p.contentEdges()
+		p.Line(left, p.GetY(), right, p.GetY(), p.foreground, p.mm(0.2))
+		p.Ln(1)
+	}
+}
+
+// used to write a paragraph containing styled text as rich text, images
+// are placed on their own line between the text before and after them
+func (p *Pdfb) markdownParagraph(n ast.Node, source []byte) {
+	var spans []Span
+
+	// used to write the spans collected so far
+	flush := func() {
+		if len(spans) > 0 {
+			p.RichText(spans...)
+			spans = nil
+		}
+	}
+
+	p.markdownSpans(n, source, Span{}, &spans, func(dest string) {
+		flush()
+		p.markdownImage(dest)
+	})
+	flush()
+}
+
+// used to collect the spans of the inline nodes of n, style is the span
+// style of the nodes that n is nested in, images are passed to image, or
+// written as their text if image is nil
+func (p *Pdfb) markdownSpans(n ast.Node, source []byte, style Span, spans *[]Span, image func(dest string)) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			style.Text = string(c.Segment.Value(source))
+			switch {
+			case c.HardLineBreak():
+				style.Text += "\n"
+			case c.SoftLineBreak():
+				style.Text += " "
+			}
+			*spans = append(*spans, style)
+
+		case *ast.String:
+			style.Text = string(c.Value)
+			*spans = append(*spans, style)
+
+		case *ast.Emphasis:
+			emphasis := style
+			if c.Level >= 2 {
+				emphasis.Bold = true
+			} else {
+				emphasis.Italic = true
+			}
+			p.markdownSpans(c, source, emphasis, spans, image)
+
+		case *ast.CodeSpan:
+			code := style
+			code.Family = p.theme.Code.Font.Family
+			if p.theme.Code.Colour != "" {
+				code.Colour = p.themeColour(p.theme.Code.Colour)
+			}
+			code.Text = markdownText(c, source)
+			*spans = append(*spans, code)
+
+		case *ast.Link:
+			link := style
+			link.Link = string(c.Destination)
+			p.markdownSpans(c, source, link, spans, image)
+
+		case *ast.AutoLink:
+			link := style
+			link.Link = string(c.URL(source))
+			link.Text = string(c.Label(source))
+			*spans = append(*spans, link)
+
+		case *ast.Image:
+			if image == nil {
+				p.markdownSpans(c, source, style, spans, image)
+				continue
+			}
+			image(string(c.Destination))
+
+		default:
+			p.markdownSpans(c, source, style, spans, image)
+		}
+	}
+}
+
+// used to place an image on its own line, at its natural width unless
+// it is wider than the page
+func (p *Pdfb) markdownImage(dest string) {
+	left, right := p.contentEdges()
+	w := right - left
+	if fileExists(dest) {
+		if info := p.pdf.RegisterImage(dest, ""); info != nil && info.Width() < w {
+			w = info.Width()
+		}
+	}
+	p.SetX(left)
+	p.Image(dest, "c", left, p.GetY(), w, 0)
+}
+
+// used to flatten a markdown list into list items, nested lists
+// become items of the next level, items containing styled text are
+// written as rich text
+func (p *Pdfb) markdownListItems(list *ast.List, source []byte, level int, items *[]ListItem) {
+	for li := list.FirstChild(); li != nil; li = li.NextSibling() {
+		var texts []string
+		var spans []Span
+		var nested []*ast.List
+		plain := true
+		for c := li.FirstChild(); c != nil; c = c.NextSibling() {
+			if l, ok := c.(*ast.List); ok {
+				nested = append(nested, l)
+				continue
+			}
+			if len(texts) > 0 {
+				spans = append(spans, Span{Text: " "})
+			}
+			texts = append(texts, markdownText(c, source))
+			p.markdownSpans(c, source, Span{}, &spans, nil)
+			plain = plain && isPlainMarkdown(c)
+		}
+
+		item := ListItem{Level: level, Text: strings.Join(texts, " ")}
+		if !plain {
+			item.Spans = spans
+		}
+		*items = append(*items, item)
+		for _, l := range nested {
+			p.markdownListItems(l, source, level+1, items)
+		}
+	}
+}
+
+// used to get the plain text of a markdown node
+func markdownText(n ast.Node, source []byte) string {
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			sb.Write(c.Segment.Value(source))
+			if c.SoftLineBreak() || c.HardLineBreak() {
+				sb.WriteString(" ")
+			}
+		case *ast.String:
+			sb.Write(c.Value)
+		case *ast.AutoLink:
+			sb.Write(c.Label(source))
+		default:
+			sb.WriteString(markdownText(c, source))
+		}
+	}
+	return sb.String()
+}
+
+// used to check whether a block only contains plain text
+func isPlainMarkdown(n ast.Node) bool {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			if c.HardLineBreak() {
+				return false
+			}
+		case *ast.String:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package pdfb
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NumberFormat defines how a number is written
+type NumberFormat int
+
+// Number formats
+const (
+	Decimal    NumberFormat = iota // 1, 2, 3
+	LowerAlpha                     // a, b, c
+	UpperAlpha                     // A, B, C
+	LowerRoman                     // i, ii, iii
+	UpperRoman                     // I, II, III
+	NoNumber                       // not written
+)
+
+// Format is used to write n in the number format
+// Numbers that can't be written as letters or roman numerals (n < 1)
+// are written as decimals
+func (f NumberFormat) Format(n int) string {
+	if f == NoNumber {
+		return ""
+	}
+	if n < 1 {
+		return strconv.Itoa(n)
+	}
+	switch f {
+	case LowerAlpha:
+		return strings.ToLower(toAlpha(n))
+	case UpperAlpha:
+		return toAlpha(n)
+	case LowerRoman:
+		return strings.ToLower(toRoman(n))
+	case UpperRoman:
+		return toRoman(n)
+	}
+	return strconv.Itoa(n)
+}
+
+// HeadingNumbering defines how headings are numbered
+// Formats gives the number format of each level starting at level 1, the
+// last format is used for any deeper levels, and levels using NoNumber are
+// left out of the numbers of the levels below them. Headings deeper than
+// MaxLevel are not numbered (0 numbers every level).
+// eg. HeadingNumbering{Formats: []NumberFormat{UpperRoman, Decimal}} gives II.3
+type HeadingNumbering struct {
+	Formats   []NumberFormat
+	Separator string
+	MaxLevel  int
+}
+
+// WithHeadingNumbering is used to number headings when creating a document
+func WithHeadingNumbering(numbering HeadingNumbering) Option {
+	return func(p *Pdfb) {
+		p.SetHeadingNumbering(numbering)
+	}
+}
+
+// SetHeadingNumbering is used to number the headings that follow
+// The number is written before the heading text, and is included in the
+// ToC and bookmarks. The separator defaults to ".".
+func (p *Pdfb) SetHeadingNumbering(numbering HeadingNumbering) {
+	if numbering.Separator == "" {
+		numbering.Separator = "."
+	}
+	if len(numbering.Formats) == 0 {
+		numbering.Formats = []NumberFormat{Decimal}
+	}
+	p.headingNumbering = &numbering
+	p.checkpoint("Heading numbering set")
+}
+
+// ClearHeadingNumbering is used to stop numbering the headings that follow
+func (p *Pdfb) ClearHeadingNumbering() {
+	p.headingNumbering = nil
+}
+
+// used to count a heading and get its number, which is empty when
+// the heading isn't numbered
+func (p *Pdfb) nextHeadingNumber(level int) string {
+	n := p.headingNumbering
+	if n == nil || (n.MaxLevel > 0 && level > n.MaxLevel) {
+		return ""
+	}
+
+	// counters of deeper levels are reset when a heading is counted
+	for len(p.headingCounters) < level {
+		p.headingCounters = append(p.headingCounters, 0)
+	}
+	p.headingCounters = p.headingCounters[:level]
+	p.headingCounters[level-1]++
+
+	var parts []string
+	for l, counter := range p.headingCounters {
+		format := n.Formats[len(n.Formats)-1]
+		if l < len(n.Formats) {
+			format = n.Formats[l]
+		}
+		// a skipped level counts as its first heading, so the next
+		// heading at that level is its second
+		if counter == 0 {
+			counter = 1
+			p.headingCounters[l] = counter
+		}
+		if s := format.Format(counter); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, n.Separator)
+}
+
+// used to write n as letters, eg. 1 = A, 26 = Z, 27 = AA
+func toAlpha(n int) (s string) {
+	for n > 0 {
+		n--
+		s = string(rune('A'+n%26)) + s
+		n /= 26
+	}
+	return
+}
+
+// used to write n as roman numerals, eg. 14 = XIV
+func toRoman(n int) string {
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	numerals := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+
+	var sb strings.Builder
+	for i, v := range values {
+		for n >= v {
+			sb.WriteString(numerals[i])
+			n -= v
+		}
+	}
+	return sb.String()
+}
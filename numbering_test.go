@@ -0,0 +1,73 @@
+package pdfb
+
+import "testing"
+
+func TestToRoman(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{1, "I"},
+		{3, "III"},
+		{4, "IV"},
+		{9, "IX"},
+		{14, "XIV"},
+		{40, "XL"},
+		{90, "XC"},
+		{400, "CD"},
+		{1994, "MCMXCIV"},
+		{3999, "MMMCMXCIX"},
+	}
+	for _, tt := range tests {
+		if got := toRoman(tt.n); got != tt.want {
+			t.Errorf("toRoman(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestToAlpha(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{1, "A"},
+		{2, "B"},
+		{26, "Z"},
+		{27, "AA"},
+		{52, "AZ"},
+		{53, "BA"},
+		{702, "ZZ"},
+		{703, "AAA"},
+	}
+	for _, tt := range tests {
+		if got := toAlpha(tt.n); got != tt.want {
+			t.Errorf("toAlpha(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestNumberFormat(t *testing.T) {
+	tests := []struct {
+		format NumberFormat
+		n      int
+		want   string
+	}{
+		{Decimal, 1, "1"},
+		{Decimal, 42, "42"},
+		{LowerAlpha, 3, "c"},
+		{UpperAlpha, 28, "AB"},
+		{LowerRoman, 4, "iv"},
+		{UpperRoman, 12, "XII"},
+		{NoNumber, 5, ""},
+
+		// numbers below 1 can't be written as letters or roman numerals
+		{LowerRoman, 0, "0"},
+		{UpperAlpha, -2, "-2"},
+		{NoNumber, 0, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.format.Format(tt.n); got != tt.want {
+			t.Errorf("NumberFormat(%d).Format(%d) = %q, want %q", tt.format, tt.n, got, tt.want)
+		}
+	}
+}
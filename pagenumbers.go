@@ -0,0 +1,164 @@
+package pdfb
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pageSection is used to define a run of pages that are numbered together,
+// starting at the first page
+type pageSection struct {
+	first  int
+	format NumberFormat
+	start  int
+}
+
+// SetPageNumbering is used to start numbering pages again from the next page
+// added, the pages are numbered from start in the format, eg. LowerRoman for
+// front matter followed by Decimal from 1 for the body
+// The numbers are used for {page} in headers and footers, in the ToC, the
+// index and references, and are shown by PDF viewers as page labels. The
+// start defaults to 1.
+func (p *Pdfb) SetPageNumbering(format NumberFormat, start int) {
+	if start < 1 {
+		start = 1
+	}
+	section := pageSection{first: p.pdf.PageNo() + 1, format: format, start: start}
+
+	// a section without any pages is replaced
+	if last := len(p.pageSections) - 1; p.pageSections[last].first == section.first {
+		p.pageSections[last] = section
+	} else {
+		p.pageSections = append(p.pageSections, section)
+	}
+
+	p.checkpoint("Page numbering set")
+}
+
+// used to get the index of the numbering section that a page is in
+func (p *Pdfb) pageSectionIndex(page int) int {
+	i := len(p.pageSections) - 1
+	for i > 0 && p.pageSections[i].first > page {
+		i--
+	}
+	return i
+}
+
+// used to get the number of a page as it is written
+func (p *Pdfb) pageLabel(page int) string {
+	s := p.pageSections[p.pageSectionIndex(page)]
+	return s.format.Format(s.start + page - s.first)
+}
+
+// used to get the alias that is replaced with the number of pages in the
+// numbering section that a page is in
+func (p *Pdfb) sectionPagesAlias(page int) string {
+	return fmt.Sprintf("{sectionpages:%d}", p.pageSectionIndex(page))
+}
+
+// used to replace the alias of each numbering section with its number of
+// pages, written in the format of the section
+func (p *Pdfb) registerSectionPages() {
+	pages := p.pdf.PageCount()
+	for i, s := range p.pageSections {
+		last := pages
+		if i+1 < len(p.pageSections) {
+			last = p.pageSections[i+1].first - 1
+		}
+		count := last - s.first + 1
+		if count < 0 {
+			count = 0
+		}
+		p.pdf.RegisterAlias(fmt.Sprintf("{sectionpages:%d}", i), s.format.Format(count))
+	}
+}
+
+// the page label styles of each number format
+var pageLabelStyles = map[NumberFormat]string{
+	Decimal:    "/D",
+	LowerRoman: "/r",
+	UpperRoman: "/R",
+	LowerAlpha: "/a",
+	UpperAlpha: "/A",
+}
+
+var (
+	trailerRootRe = regexp.MustCompile(`/Root (\d+) 0 R`)
+	trailerSizeRe = regexp.MustCompile(`/Size (\d+)`)
+	trailerInfoRe = regexp.MustCompile(`/Info (\d+) 0 R`)
+)
+
+// used to add the page numbering to a rendered document as page labels, so
+// that viewers show the same numbers as the pages
+// gofpdf can't write page labels, so the catalog is written again with them
+// in an incremental update at the end of the document.
+func (p *Pdfb) addPageLabels(doc []byte) ([]byte, error) {
+	// nothing to add when the pages are numbered the default way
+	if len(p.pageSections) == 1 && p.pageSections[0] == (pageSection{first: 1, format: Decimal, start: 1}) {
+		return doc, nil
+	}
+
+	// the objects of the document are found from the trailer
+	trailerAt := bytes.LastIndex(doc, []byte("trailer"))
+	startxrefAt := bytes.LastIndex(doc, []byte("startxref"))
+	if trailerAt < 0 || startxrefAt < trailerAt {
+		return nil, fmt.Errorf("page labels: trailer not found")
+	}
+	trailer := doc[trailerAt:startxrefAt]
+	rootMatch := trailerRootRe.FindSubmatch(trailer)
+	sizeMatch := trailerSizeRe.FindSubmatch(trailer)
+	if rootMatch == nil || sizeMatch == nil {
+		return nil, fmt.Errorf("page labels: trailer has no root")
+	}
+	var prev int
+	if _, err := fmt.Sscan(string(doc[startxrefAt+len("startxref"):]), &prev); err != nil {
+		return nil, fmt.Errorf("page labels: %w", err)
+	}
+
+	// the catalog, without the end of its dictionary
+	root := string(rootMatch[1])
+	objStart := []byte("\n" + root + " 0 obj\n")
+	catalogAt := bytes.Index(doc, objStart)
+	if catalogAt < 0 {
+		return nil, fmt.Errorf("page labels: catalog not found")
+	}
+	catalogAt += len(objStart)
+	catalogEnd := bytes.Index(doc[catalogAt:], []byte("\nendobj"))
+	if catalogEnd < 0 {
+		return nil, fmt.Errorf("page labels: catalog not found")
+	}
+	catalog := strings.TrimSpace(string(doc[catalogAt : catalogAt+catalogEnd]))
+	catalog = strings.TrimSpace(strings.TrimSuffix(catalog, ">>"))
+
+	// the label of each section, with the index of its first page
+	var nums []string
+	for _, s := range p.pageSections {
+		label := "<<"
+		if style, ok := pageLabelStyles[s.format]; ok {
+			label += " /S " + style
+		}
+		if s.start != 1 {
+			label += " /St " + strconv.Itoa(s.start)
+		}
+		nums = append(nums, fmt.Sprintf("%d %s >>", s.first-1, label))
+	}
+
+	out := bytes.NewBuffer(doc)
+	if !bytes.HasSuffix(doc, []byte("\n")) {
+		out.WriteString("\n")
+	}
+	offset := out.Len()
+	fmt.Fprintf(out, "%s 0 obj\n%s\n/PageLabels << /Nums [%s] >>\n>>\nendobj\n", root, catalog, strings.Join(nums, " "))
+	xref := out.Len()
+	fmt.Fprintf(out, "xref\n0 1\n0000000000 65535 f \n%s 1\n%010d 00000 n \n", root, offset)
+	fmt.Fprintf(out, "trailer\n<<\n/Size %s\n/Root %s 0 R\n", sizeMatch[1], root)
+	if infoMatch := trailerInfoRe.FindSubmatch(trailer); infoMatch != nil {
+		fmt.Fprintf(out, "/Info %s 0 R\n", infoMatch[1])
+	}
+	fmt.Fprintf(out, "/Prev %d\n>>\nstartxref\n%d\n%%%%EOF\n", prev, xref)
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,158 @@
+package pdfb
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// used to build a document with roman front matter followed by a body
+// numbered from 1
+func pageLabelsDoc(t *testing.T) []byte {
+	t.Helper()
+	p := New()
+	p.SetPageNumbering(LowerRoman, 1)
+	p.Page()
+	p.WriteLn("Title")
+	p.Page()
+	p.WriteLn("Preface")
+	p.SetPageNumbering(Decimal, 1)
+	for i := 0; i < 3; i++ {
+		p.Page()
+		p.WriteLn("Body")
+	}
+	doc, err := p.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// used to get the number written after the last startxref before end
+func startxref(t *testing.T, doc []byte, end int) int {
+	t.Helper()
+	at := bytes.LastIndex(doc[:end], []byte("startxref"))
+	if at < 0 {
+		t.Fatal("startxref not found")
+	}
+	var offset int
+	if _, err := fmt.Sscan(string(doc[at+len("startxref"):]), &offset); err != nil {
+		t.Fatal(err)
+	}
+	return offset
+}
+
+func TestPageLabels(t *testing.T) {
+	doc := pageLabelsDoc(t)
+
+	// the update comes after the original document
+	if n := bytes.Count(doc, []byte("%%EOF")); n != 2 {
+		t.Fatalf("got %d %%%%EOF markers, want 2", n)
+	}
+	firstEnd := bytes.Index(doc, []byte("%%EOF"))
+
+	// the last startxref points to the xref of the update
+	xref := startxref(t, doc, len(doc))
+	if !bytes.HasPrefix(doc[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d doesn't point to an xref", xref)
+	}
+
+	// /Prev points to the xref of the original document
+	trailer := doc[xref:]
+	m := regexp.MustCompile(`/Prev (\d+)`).FindSubmatch(trailer)
+	if m == nil {
+		t.Fatal("trailer has no /Prev")
+	}
+	prev, _ := strconv.Atoi(string(m[1]))
+	if want := startxref(t, doc, firstEnd); prev != want {
+		t.Errorf("/Prev = %d, want %d", prev, want)
+	}
+	if !bytes.HasPrefix(doc[prev:], []byte("xref\n")) {
+		t.Errorf("/Prev %d doesn't point to an xref", prev)
+	}
+
+	// the xref gives the offset of the new catalog, which is the root
+	m = regexp.MustCompile(`xref\n0 1\n0000000000 65535 f \n(\d+) 1\n(\d{10}) 00000 n \n`).FindSubmatch(trailer)
+	if m == nil {
+		t.Fatalf("xref of the update not understood:\n%s", trailer)
+	}
+	root := string(m[1])
+	offset, _ := strconv.Atoi(string(m[2]))
+	if !bytes.HasPrefix(doc[offset:], []byte(root+" 0 obj\n")) {
+		t.Errorf("xref offset %d doesn't point to object %s", offset, root)
+	}
+	if !bytes.Contains(trailer, []byte("/Root "+root+" 0 R")) {
+		t.Errorf("trailer root isn't object %s", root)
+	}
+
+	// the catalog still has its pages, along with the labels, the front
+	// matter starts at the first page and the body at the third
+	catalog := doc[offset:xref]
+	if !bytes.Contains(catalog, []byte("/Type /Catalog")) || !bytes.Contains(catalog, []byte("/Pages ")) {
+		t.Errorf("catalog lost its entries:\n%s", catalog)
+	}
+	want := "/PageLabels << /Nums [0 << /S /r >> 2 << /S /D >>] >>"
+	if !bytes.Contains(catalog, []byte(want)) {
+		t.Errorf("catalog has no %q:\n%s", want, catalog)
+	}
+}
+
+func TestPageLabelsStart(t *testing.T) {
+	p := New()
+	p.Page()
+	p.SetPageNumbering(UpperAlpha, 3)
+	p.Page()
+	doc, err := p.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "/PageLabels << /Nums [0 << /S /D >> 1 << /S /A /St 3 >>] >>"
+	if !bytes.Contains(doc, []byte(want)) {
+		t.Errorf("document has no %q", want)
+	}
+}
+
+func TestPageLabelsDefault(t *testing.T) {
+	p := New()
+	p.Page()
+	p.Page()
+	doc, err := p.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(doc, []byte("/PageLabels")) {
+		t.Error("page labels added to a document numbered the default way")
+	}
+	if n := bytes.Count(doc, []byte("%%EOF")); n != 1 {
+		t.Errorf("got %d %%%%EOF markers, want 1", n)
+	}
+}
+
+func TestPageLabel(t *testing.T) {
+	p := New()
+	p.SetPageNumbering(LowerRoman, 1)
+	p.Page()
+	p.Page()
+	p.Page()
+	p.SetPageNumbering(Decimal, 1)
+	p.Page()
+	p.Page()
+
+	tests := []struct {
+		page int
+		want string
+	}{
+		{1, "i"},
+		{2, "ii"},
+		{3, "iii"},
+		{4, "1"},
+		{5, "2"},
+	}
+	for _, tt := range tests {
+		if got := p.pageLabel(tt.page); got != tt.want {
+			t.Errorf("pageLabel(%d) = %q, want %q", tt.page, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package pdfb
+
+import "strings"
+
+// pageDimensions is used to define a named page size, in millimetres and
+// portrait
+type pageDimensions struct {
+	name string
+	w, h float64
+}
+
+// the page sizes that can be set by name, keyed by their name in lower case
+// without spaces
+var pageSizes = map[string]pageDimensions{
+	// ISO A series
+	"a0":  {"A0", 841, 1189},
+	"a1":  {"A1", 594, 841},
+	"a2":  {"A2", 420, 594},
+	"a3":  {"A3", 297, 420},
+	"a4":  {"A4", 210, 297},
+	"a5":  {"A5", 148, 210},
+	"a6":  {"A6", 105, 148},
+	"a7":  {"A7", 74, 105},
+	"a8":  {"A8", 52, 74},
+	"a9":  {"A9", 37, 52},
+	"a10": {"A10", 26, 37},
+
+	// ISO B series
+	"b0":  {"B0", 1000, 1414},
+	"b1":  {"B1", 707, 1000},
+	"b2":  {"B2", 500, 707},
+	"b3":  {"B3", 353, 500},
+	"b4":  {"B4", 250, 353},
+	"b5":  {"B5", 176, 250},
+	"b6":  {"B6", 125, 176},
+	"b7":  {"B7", 88, 125},
+	"b8":  {"B8", 62, 88},
+	"b9":  {"B9", 44, 62},
+	"b10": {"B10", 31, 44},
+
+	// ISO C series, used for envelopes
+	"c0":  {"C0", 917, 1297},
+	"c1":  {"C1", 648, 917},
+	"c2":  {"C2", 458, 648},
+	"c3":  {"C3", 324, 458},
+	"c4":  {"C4", 229, 324},
+	"c5":  {"C5", 162, 229},
+	"c6":  {"C6", 114, 162},
+	"c7":  {"C7", 81, 114},
+	"c8":  {"C8", 57, 81},
+	"c9":  {"C9", 40, 57},
+	"c10": {"C10", 28, 40},
+
+	// US sizes
+	"letter":    {"Letter", 215.9, 279.4},
+	"legal":     {"Legal", 215.9, 355.6},
+	"tabloid":   {"Tabloid", 279.4, 431.8},
+	"ledger":    {"Ledger", 279.4, 431.8},
+	"executive": {"Executive", 184.15, 266.7},
+	"statement": {"Statement", 139.7, 215.9},
+
+	// US ANSI sizes
+	"ansia": {"ANSI A", 215.9, 279.4},
+	"ansib": {"ANSI B", 279.4, 431.8},
+	"ansic": {"ANSI C", 431.8, 558.8},
+	"ansid": {"ANSI D", 558.8, 863.6},
+	"ansie": {"ANSI E", 863.6, 1117.6},
+
+	// envelopes
+	"dl":         {"DL", 110, 220},
+	"envelope10": {"Envelope10", 104.775, 241.3},
+	"monarch":    {"Monarch", 98.425, 190.5},
+
+	// business cards
+	"businesscard":    {"BusinessCard", 50.8, 88.9},
+	"businesscardiso": {"BusinessCardISO", 55, 85},
+}
+
+// used to get the key of a page size name, so that names are matched
+// without case or spaces, eg. "ANSI C" and "ansic"
+func pageSizeKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, " ", ""))
+}
+
+// used to get the name, width and height of a page size in portrait, in
+// the unit of the document
+func (p *Pdfb) lookupPageSize(pageSize string) (name string, w, h float64, ok bool) {
+	size, ok := pageSizes[pageSizeKey(pageSize)]
+	if !ok {
+		return "", 0, 0, false
+	}
+	return size.name, p.mm(size.w), p.mm(size.h), true
+}
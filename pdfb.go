@@ -4,27 +4,59 @@ import (
 	"bytes"
 	"encoding/base64"
 	"fmt"
+	"image"
+	"io"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/barjoio/utils/colour"
-	"github.com/barjoio/utils/log"
 	"github.com/jung-kurt/gofpdf"
 )
 
-// var err error
-
 // Pdfb is the main Pdfb struct
 type Pdfb struct {
-	pdf *gofpdf.Fpdf
-
-	bgFunc          func()
-	footerHeight    float64
-	headerHeight    float64
-	headings        []heading
-	tocPage         int
-	writingContents bool
+	pdf    *gofpdf.Fpdf
+	err    error
+	logger Logger
+	output []byte
+
+	bgFunc           func()
+	header           pageLayout
+	pageSections     []pageSection
+	footer           pageLayout
+	footerHeight     float64
+	footnotes        []footnote
+	footnoteCarry    []footnote
+	footnoteSpace    float64
+	footnoteCount    int
+	footnotePage     int
+	footnoteFormat   NumberFormat
+	footnotePerPage  bool
+	headerHeight     float64
+	headings         []heading
+	headingCounters  []int
+	headingNumbering *HeadingNumbering
+	generating       bool
+	settled          bool
+	finalised        bool
+	listings         []*listing
+	listingPages     []int
+	figures          []heading
+	tables           []heading
+	anchors          map[string]*anchor
+	refs             []ref
+	anchorEstimates  map[string]anchor
+	indexTerms       []indexTerm
+	writingContents  bool
+
+	// columns
+	columns      int
+	column       int
+	columnGutter float64
+	columnTop    float64
+	columnBottom float64
 
 	// customisable
 	accentColour     string
@@ -36,68 +68,86 @@ type Pdfb struct {
 	indentSize       float64
 	keywords         []string
 	lineHeight       float64
-	margin           float64
+	margins          Margins
 	modificationDate time.Time
 	orientation      string
 	pageHeight       float64
 	pageSize         string
 	pageWidth        float64
 	subject          string
+	theme            Theme
 	title            string
+	unit             string
 }
 
+// Option is used to configure a document when calling New
+type Option func(p *Pdfb)
+
 // New returns a PDF Builder
-func New() *Pdfb {
+func New(options ...Option) *Pdfb {
 	// PDF default options
 	p := &Pdfb{
-		pdf: gofpdf.New("P", "mm", "A4", ""),
+		logger: nopLogger{},
 
 		bgFunc:          func() {},
 		footerHeight:    0,
 		headerHeight:    0,
 		headings:        []heading{},
-		tocPage:         -1,
+		anchors:         map[string]*anchor{},
+		pageSections:    []pageSection{{first: 1, format: Decimal, start: 1}},
 		writingContents: false,
 
+		columns: 1,
+
 		accentColour:     "#f00",
 		author:           "",
 		background:       "#ffffff",
 		creationDate:     time.Now(),
-		font:             Font{Family: "Inter", Size: 12.0},
+		font:             Font{Family: defaultFontFamily, Size: 12.0},
 		foreground:       "#000000",
 		indentSize:       4,
 		keywords:         []string{},
 		lineHeight:       6.0,
-		margin:           20.0,
+		margins:          Margins{Top: 20.0, Bottom: 20.0, Left: 20.0, Right: 20.0},
 		modificationDate: time.Now(),
 		orientation:      "P",
 		pageHeight:       297.0,
 		pageSize:         "A4",
 		pageWidth:        210.0,
 		subject:          "",
+		theme:            DefaultTheme(),
 		title:            "",
+		unit:             "mm",
 	}
 
-	// import inter to be used as the default font
-	// p.pdf.AddUTF8FontFromBytes("Inter", "", decode(inter.InterRegular))
-	// p.pdf.AddUTF8FontFromBytes("Inter", "b", decode(inter.InterBold))
-	// p.pdf.AddUTF8FontFromBytes("Inter", "i", decode(inter.InterItalic))
-	// p.pdf.AddUTF8FontFromBytes("Inter", "bi", decode(inter.InterBoldItalic))
+	p.initPdf()
 
-	p.checkpoint("Inter font imported")
+	// apply user options
+	for _, option := range options {
+		option(p)
+	}
+
+	return p
+}
+
+// used to create the gofpdf document in the unit of the document, and set
+// it up with the options of the document
+func (p *Pdfb) initPdf() {
+	p.pdf = gofpdf.New("P", p.unit, "A4", "")
 
 	// pdf initialisation
 	p.pdf.SetCellMargin(0)
 	p.pdf.SetProducer("GoFPDF 2.17.2", true)
 	p.pdf.AliasNbPages("")
 	p.pdf.SetAuthor(p.author, true)
-	p.pdf.SetAutoPageBreak(true, p.margin)
+	p.pdf.SetAutoPageBreak(true, p.margins.Bottom)
 	p.pdf.SetCreator("github.com/barjoio/pdfb", true)
 	p.pdf.SetCreationDate(p.creationDate)
 	p.pdf.SetFont(p.font.Family, "", p.font.Size)
 	p.pdf.SetFontSize(p.font.Size)
 	p.pdf.SetKeywords(strings.Join(p.keywords, ";"), true)
-	p.pdf.SetMargins(p.margin, p.margin, p.margin)
+	left, right := p.sideMargins(1)
+	p.pdf.SetMargins(left, p.margins.Top, right)
 	p.pdf.SetModificationDate(p.modificationDate)
 	p.pdf.SetSubject(p.subject, true)
 	p.pdf.SetTextColor(colour.HexToRGB(p.foreground))
@@ -114,12 +164,29 @@ func New() *Pdfb {
 		p.pdf.SetFillColor(currentR, currentG, currentB)
 	}
 
-	// default header, does nothing except set the background colour
+	// the header sets the background colour, moves the margins to those
+	// of the new page, moves the cursor under the
+	// header set by SetHeader, sets aside space for footnotes carried over
+	// from the last page, and starts the columns of the new page
 	p.pdf.SetHeaderFunc(func() {
 		p.bgFunc()
+		p.startMargins()
+		p.startHeader()
+		p.startFootnotes()
+		if p.columns > 1 {
+			p.startColumns()
+		}
 	})
 
-	return p
+	// the footer prints the footnotes of the page, then the header and
+	// footer, which are printed last so that they know what is on the page
+	p.pdf.SetFooterFunc(func() {
+		p.printFootnotes()
+		p.printHeaderFooter()
+	})
+
+	// used to move on to the next column instead of the next page
+	p.pdf.SetAcceptPageBreakFunc(p.acceptPageBreak)
 }
 
 // SetAccentColour is used to set the accentColour
@@ -198,16 +265,15 @@ func (p *Pdfb) GetLineHeight() float64 {
 	return p.lineHeight
 }
 
-// SetMargin is used to set the margin
+// SetMargin is used to set all four margins to the same size
 func (p *Pdfb) SetMargin(margin float64) {
-	p.margin = margin
-	p.pdf.SetMargins(margin, margin, margin)
-	p.checkpoint("Margins set")
+	p.SetMargins(Margins{Top: margin, Bottom: margin, Left: margin, Right: margin})
 }
 
-// GetMargin is used to get the margin
+// GetMargin is used to get the margin, which is the top margin when the
+// margins aren't all the same size
 func (p *Pdfb) GetMargin() float64 {
-	return p.margin
+	return p.margins.Top
 }
 
 // SetModificationDate is used to set the modificationDate
@@ -222,9 +288,29 @@ func (p *Pdfb) GetModificationDate() time.Time {
 	return p.modificationDate
 }
 
-// SetOrientation is used to set the orientation
+// SetOrientation is used to set the orientation of the pages that are
+// added, either "P" (portrait) or "L" (landscape)
+// An error wrapping ErrInvalidOrientation is set if the orientation is not
+// recognised.
 func (p *Pdfb) SetOrientation(orientation string) {
-	p.orientation = orientation
+	o, ok := lookupOrientation(orientation)
+	if !ok {
+		p.SetErrorf("%w: %s", ErrInvalidOrientation, orientation)
+		return
+	}
+	p.orientation = o
+	p.checkpoint("Orientation set")
+}
+
+// used to get the orientation "P" or "L" from its name
+func lookupOrientation(orientation string) (string, bool) {
+	switch strings.ToLower(orientation) {
+	case "p", "portrait":
+		return "P", true
+	case "l", "landscape":
+		return "L", true
+	}
+	return "", false
 }
 
 // GetOrientation is used to get the orientation
@@ -237,55 +323,34 @@ func (p *Pdfb) SetPageHeight(pageHeight float64) {
 	p.pageHeight = pageHeight
 }
 
-// GetPageHeight is used to get the pageHeight
+// GetPageHeight is used to get the height of the current page
 func (p *Pdfb) GetPageHeight() float64 {
-	_, h := p.pdf.GetPageSize()
+	_, h := p.currentPageSize()
 	return h
 }
 
-// SetPageSize is used to set the pageSize
+// SetPageSize is used to set the pageSize, by name, eg. "A4", "B5",
+// "Letter", "ANSI C", "DL" or "BusinessCard"
+// Sizes are portrait, set the orientation to "L" to turn them on their side.
+// An error wrapping ErrInvalidPageSize is set if the size is not recognised.
 func (p *Pdfb) SetPageSize(pageSize string) {
-	p.pageSize = pageSize
-	switch strings.ToLower(pageSize) {
-	case "a1":
-		p.SetPageHeight(841.0)
-		p.SetPageWidth(594.0)
-		p.pageSize = "A1"
-	case "a2":
-		p.SetPageHeight(594.0)
-		p.SetPageWidth(420.0)
-		p.pageSize = "A2"
-	case "a3":
-		p.SetPageHeight(420.0)
-		p.SetPageWidth(297.0)
-		p.pageSize = "A3"
-	case "a4":
-		p.SetPageHeight(297.0)
-		p.SetPageWidth(210.0)
-		p.pageSize = "A4"
-	case "a5":
-		p.SetPageHeight(210.0)
-		p.SetPageWidth(148.0)
-		p.pageSize = "A5"
-	case "a6":
-		p.SetPageHeight(148.0)
-		p.SetPageWidth(105.0)
-		p.pageSize = "A6"
-	case "letter":
-		p.SetPageHeight(279.4)
-		p.SetPageWidth(215.9)
-		p.pageSize = "Letter"
-	case "legal":
-		p.SetPageHeight(355.6)
-		p.SetPageWidth(215.9)
-		p.pageSize = "Legal"
-	case "tabloid":
-		p.SetPageHeight(431.8)
-		p.SetPageWidth(279.4)
-		p.pageSize = "Tabloid"
-	default:
-		log.ErrorFatal("%s is not a valid page size.", pageSize)
+	name, w, h, ok := p.lookupPageSize(pageSize)
+	if !ok {
+		p.SetErrorf("%w: %s", ErrInvalidPageSize, pageSize)
+		return
 	}
+	p.SetPageHeight(h)
+	p.SetPageWidth(w)
+	p.pageSize = name
+	p.checkpoint("Page size set")
+}
+
+// SetPageDimensions is used to set a custom page size, in the unit of
+// the document
+func (p *Pdfb) SetPageDimensions(w, h float64) {
+	p.SetPageWidth(w)
+	p.SetPageHeight(h)
+	p.pageSize = "Custom"
 	p.checkpoint("Page size set")
 }
 
@@ -299,9 +364,9 @@ func (p *Pdfb) SetPageWidth(pageWidth float64) {
 	p.pageWidth = pageWidth
 }
 
-// GetPageWidth is used to get the pageWidth
+// GetPageWidth is used to get the width of the current page
 func (p *Pdfb) GetPageWidth() float64 {
-	w, _ := p.pdf.GetPageSize()
+	w, _ := p.currentPageSize()
 	return w
 }
 
@@ -339,140 +404,60 @@ type TextAlign struct {
 	Align string
 }
 
-// heading is used to define a heading
+// heading is used to define a heading, text includes the number
 type heading struct {
-	text  string
-	level int
-	page  int
-	link  int
+	text   string
+	title  string
+	number string
+	level  int
+	page   int
+	link   int
 }
 
-// Page is used to insert a new page
-func (p *Pdfb) Page() {
-	p.pdf.AddPageFormat(p.GetOrientation(), gofpdf.SizeType{
-		Wd: p.GetPageWidth(),
-		Ht: p.GetPageHeight(),
-	})
-	p.checkpoint("Page added")
+// HeadingOptions defines options for a heading
+// Unnumbered headings are not numbered or counted when heading numbering
+// is on. Anchor names the heading so that it can be referred to with Ref.
+type HeadingOptions struct {
+	Unnumbered bool
+	Anchor     string
 }
 
-// SetHeader is used to set the header
-func (p *Pdfb) SetHeader(fontFamily string, content ...TextAlign) {
-	pageWidth, _, _ := p.pdf.PageSize(p.pdf.PageNo())
-	sectionWidth := (pageWidth - p.margin*2) / float64(len(content))
-	p.headerHeight = 25.0
-
-	p.pdf.SetHeaderFunc(func() {
-		// copy the current font
-		currentFont := p.fontCopy(p.font)
-
-		// get current foreground
-		currentFG := p.foreground
-
-		// used to draw the background colour
-		p.bgFunc()
-
-		// put the header at the top of the page
-		p.SetY(0)
-
-		// set font for header text
-		p.SetFont(Font{
-			Family: fontFamily,
-			Size:   12,
-		})
-
-		// set foreground
-		p.SetForeground("#000")
-
-		// create cells for each section
-		for _, c := range content {
-			p.pdf.CellFormat(sectionWidth, p.headerHeight, c.Text, "", 0, "M"+p.makeAlignStr(c.Align), false, 0, "")
-		}
-
-		// set the font back to how it was
-		p.SetFont(currentFont)
-
-		// set foreground back to how it was
-		p.SetForeground(currentFG)
-
-		// set cursor to the bottom of the header
-		p.pdf.SetX(p.margin)
-		p.pdf.SetY(p.headerHeight)
-
-		p.checkpoint("Header printed")
+// Page is used to insert a new page, with the page size and orientation
+// of the document
+func (p *Pdfb) Page() {
+	p.pdf.AddPageFormat(p.orientation, gofpdf.SizeType{
+		Wd: p.pageWidth,
+		Ht: p.pageHeight,
 	})
-
-	p.checkpoint("Header set")
+	p.checkpoint("Page added")
 }
 
-// SetFooter is used to set the footer
-// The page number and number of pages can be used in the footer
-// using {pages} and {pages}.
-//
-// Eg. "Page {page} of {pages}"
-func (p *Pdfb) SetFooter(fontFamily string, content ...TextAlign) {
-	pageWidth, pageHeight, _ := p.pdf.PageSize(p.pdf.PageNo())
-	sectionWidth := (pageWidth - p.margin*2) / float64(len(content))
-	p.footerHeight = 25.0
-
-	triggeredPage := p.pdf.PageNo()
-
-	p.pdf.SetFooterFunc(func() {
-		// don't run on the page that SetFooter was called on, in order to match the behaviour of SetHeader
-		if p.pdf.PageNo() == triggeredPage {
+// PageFormat is used to insert a new page with a different page size or
+// orientation to the document, eg. a landscape page for a wide table, or
+// an A3 fold-out
+// Pages that follow from automatic page breaks keep the size and
+// orientation, and the next page added with Page goes back to those of the
+// document. Either can be left empty to use the one of the document.
+func (p *Pdfb) PageFormat(pageSize, orientation string) {
+	w, h := p.pageWidth, p.pageHeight
+	if pageSize != "" {
+		var ok bool
+		if _, w, h, ok = p.lookupPageSize(pageSize); !ok {
+			p.SetErrorf("%w: %s", ErrInvalidPageSize, pageSize)
 			return
 		}
-
-		// copy the current font
-		currentFont := p.fontCopy(p.font)
-
-		// get current foreground
-		currentFG := p.foreground
-
-		// set cursor to the position where the top of the footer starts drawing
-		p.SetY(pageHeight - p.footerHeight)
-
-		// set font for header text
-		p.SetFont(Font{
-			Family: fontFamily,
-			Size:   12,
-		})
-
-		// set foreground
-		p.SetForeground("#000")
-
-		// create cells for each section
-		for _, c := range content {
-			// deal with offset caused by width of text being calculated
-			// with the {page} and {pages} aliases (resulting text is shorter)
-			var offset float64
-			if strings.Contains(c.Text, "{page}") {
-				c.Text = strings.ReplaceAll(c.Text, "{page}", strconv.Itoa(p.pdf.PageNo()))
-				offset += p.pdf.GetStringWidth("{page}")
-			}
-			if strings.Contains(c.Text, "{pages}") {
-				offset += p.pdf.GetStringWidth("{pages}")
-			}
-			offset /= 2
-			offset -= p.pdf.GetStringWidth("00") / 2
-			p.SetX(p.GetX() + offset)
-			// print
-			p.pdf.CellFormat(sectionWidth-offset, p.footerHeight, c.Text, "", 0, "M"+p.makeAlignStr(c.Align), false, 0, "")
+	}
+	o := p.orientation
+	if orientation != "" {
+		var ok bool
+		if o, ok = lookupOrientation(orientation); !ok {
+			p.SetErrorf("%w: %s", ErrInvalidOrientation, orientation)
+			return
 		}
+	}
 
-		// set the font back to how it was
-		p.SetFont(currentFont)
-
-		// set foreground back to how it was
-		p.SetForeground(currentFG)
-
-		p.checkpoint("Footer printed")
-	})
-
-	// set the space from the bottom where the auto page break gets triggered
-	p.pdf.SetAutoPageBreak(true, p.footerHeight)
-
-	p.checkpoint("Footer set")
+	p.pdf.AddPageFormat(o, gofpdf.SizeType{Wd: w, Ht: h})
+	p.checkpoint("Page added")
 }
 
 // SetX is used to set the cursor's horizontal position
@@ -516,14 +501,14 @@ func (p *Pdfb) Box(x, y, w, h float64, hex string, fill, border bool) {
 
 // BoxInline is used to draw a box inline
 func (p *Pdfb) BoxInline(w, h float64, hex string, fill, border bool) {
-	pageWidth := p.GetPageWidth() - p.margin*2
+	left, right := p.contentEdges()
 	currentX, currentY := p.GetX(), p.GetY()
 	p.Box(currentX, currentY, w, h, hex, fill, border)
-	if currentX+w < pageWidth {
+	if currentX+w < right {
 		p.SetX(currentX + w)
 	} else {
 		p.SetY(currentY + h)
-		p.SetX(p.margin)
+		p.SetX(left)
 	}
 }
 
@@ -597,32 +582,102 @@ func (p *Pdfb) Paragraph(format string, a ...interface{}) {
 }
 
 // SaveAs is used to save the PDF document to a file
-func (p *Pdfb) SaveAs(filePath string) {
-	p.finalFunc()
-
+// The file is only created or written once the document has been rendered,
+// so an existing file is left as it was if there is an error.
+func (p *Pdfb) SaveAs(filePath string) error {
 	// output file
-	fmt.Println("Saving PDF...")
-	err := p.pdf.OutputFileAndClose(filePath)
-	log.ReportFatal(err)
-	log.Info("PDF saved to %s.", filePath)
+	p.logf(LogDebug, "Saving PDF...")
+	if err := p.render(); err != nil {
+		return err
+	}
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(p.output); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	p.logf(LogInfo, "PDF saved to %s.", filePath)
 
 	p.checkpoint("Document saved")
+	return nil
+}
+
+// WriteTo is used to write the PDF document to w
+// The document is only rendered once, so it can be written more than once
+func (p *Pdfb) WriteTo(w io.Writer) (int64, error) {
+	if err := p.render(); err != nil {
+		return 0, err
+	}
+	n, err := w.Write(p.output)
+	return int64(n), err
+}
+
+// Bytes is used to get the PDF document as raw bytes
+// The bytes are a copy, so they can be changed without affecting the
+// document.
+func (p *Pdfb) Bytes() ([]byte, error) {
+	if err := p.render(); err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), p.output...), nil
+}
+
+// render runs finalFunc and outputs the document, this only happens once
+// since the pdf cannot be output again after it has been closed
+func (p *Pdfb) render() error {
+	if p.output != nil || p.Err() {
+		return p.Error()
+	}
+
+	// documents from Generate have already been finalised
+	if !p.finalised {
+		p.finalFunc()
+	}
+
+	buf := new(bytes.Buffer)
+	if err := p.pdf.Output(buf); err != nil {
+		p.SetError(err)
+		return err
+	}
+	output, err := p.addPageLabels(buf.Bytes())
+	if err != nil {
+		p.SetError(err)
+		return err
+	}
+	p.output = output
+
+	p.checkpoint("Document rendered")
+	return nil
 }
 
 // Heading is used to write headings of various levels
-func (p *Pdfb) Heading(level int, str string) {
+// When heading numbering is on, the number of the heading is written
+// before its text
+func (p *Pdfb) Heading(level int, str string, options ...HeadingOptions) {
 	// level must be 1-6
 	if level < 1 || level > 6 {
-		log.ErrorFatal("Invalid level supplied to Heading (%d)", level)
+		p.SetErrorf("%w: %d", ErrInvalidHeadingLevel, level)
+		return
 	}
 
-	// create heading link
-	headingLink := p.pdf.AddLink()
-	p.pdf.SetLink(headingLink, p.GetY(), p.pdf.PageNo())
+	var opts HeadingOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
 
-	// add bookmark
-	if !p.writingContents {
-		p.pdf.Bookmark(str, level-1, -1)
+	// number the heading
+	title := str
+	var number string
+	if !opts.Unnumbered && !p.writingContents {
+		number = p.nextHeadingNumber(level)
+	}
+	if number != "" {
+		str = number + " " + str
 	}
 
 	// copy current font
@@ -630,109 +685,109 @@ func (p *Pdfb) Heading(level int, str string) {
 	currentLH := p.lineHeight
 	currentForeground := p.foreground
 
-	// pick font size
-	var fontSize float64
-	switch level {
-	case 6:
-		fontSize = 12
-	case 5:
-		fontSize = 12.5
-	case 4:
-		fontSize = 13.5
-	case 3:
-		fontSize = 15
-	case 2:
-		fontSize = 17
-	case 1:
-		fontSize = 19.5
-	}
-
-	// set font and write content
-	p.SetFont(Font{
-		Family: p.font.Family,
-		Bold:   true,
-		Size:   fontSize,
-	})
+	// set the font of the heading level from the theme
+	style := p.theme.Headings[level-1]
+	p.SetFont(style.Font)
+
+	// space above the heading, not needed at the top of a page, where
+	// content starts under the header
+	if style.SpaceBefore > 0 && p.GetY() > p.topSpace() {
+		p.SetY(p.GetY() + p.lineHeight*style.SpaceBefore)
+	}
 
 	// check that the heading height + height of 1 line of regular text
 	// can fit before the end of the page(-margin) or the footer if present
-	// // choose whether bottomSpace should be end of page - margin
-	// // or the height of the footer
-	var bottomSpace float64
-	if p.footerHeight > 0 {
-		bottomSpace = p.footerHeight
-	} else {
-		bottomSpace = p.margin
-	}
 	// // do the check and print line if necessary
 	// // description of the check:
 	// // p.lineHeight = lineheight of the heading
 	// // currentLH = lineheight of the previous text (and future text)
 	// // currentLH/4 = an extra quarter of a lineheight space for line
-	if (p.pdf.GetY() + p.lineHeight + currentLH + currentLH/4) > (p.GetPageHeight() - bottomSpace) {
-		p.Ln(1)
+	if (p.pdf.GetY() + p.lineHeight + currentLH + currentLH/4) > (p.GetPageHeight() - p.bottomSpace()) {
+		p.pageBreak()
 	}
 
-	// set foreground for heading level 1
-	if level == 1 {
-		p.SetForeground(p.accentColour)
+	// create heading link, once the heading's page is known
+	headingY, headingPage := p.GetY(), p.pdf.PageNo()
+	headingLink := p.pdf.AddLink()
+	p.pdf.SetLink(headingLink, headingY, headingPage)
+
+	// add bookmark
+	if !p.writingContents {
+		p.pdf.Bookmark(str, level-1, -1)
 	}
 
 	// write heading
-	p.WriteLn(str)
-
-	// draw line under for heading level 1
-	if level == 1 {
-		p.Line(p.margin, p.GetY(), p.GetPageWidth()-p.margin, p.GetY(), p.accentColour, 0.5)
-		p.SetY(p.GetY() + p.lineHeight*0.25) // larger gap below heading due to line
-	} else {
-		p.SetY(p.GetY() + p.lineHeight*0.1) // gap below heading
+	p.SetForeground(p.themeColour(style.Colour))
+	p.WriteLn("%s", str)
+
+	// draw line under the heading
+	if style.Rule {
+		weight := p.mm(style.RuleWeight)
+		if weight == 0 {
+			weight = p.mm(0.5)
+		}
+		left, right := p.contentEdges()
+		p.Line(left, p.GetY(), right, p.GetY(), p.themeColour(style.RuleColour), weight)
 	}
 
+	// gap below heading
+	p.SetY(p.GetY() + p.lineHeight*style.SpaceAfter)
+
 	// set font back to how it was
 	p.SetFont(currentFont)
 	p.SetLineHeight(currentLH)
 	p.SetForeground(currentForeground)
 
-	// add heading to headings array
-	p.headings = append(p.headings, heading{str, level, p.pdf.PageNo(), headingLink})
-
-	p.checkpoint("Heading created")
-}
+	// add heading to headings array, the titles of listings are left out
+	// since they are written once the rest of the document is done
+	if !p.writingContents {
+		p.headings = append(p.headings, heading{str, title, number, level, headingPage, headingLink})
+	}
 
-// ToC is used to generate table of contents from headings
-func (p *Pdfb) ToC(numPages int) {
-	// insert number of pages for toc
-	p.tocPage = p.pdf.PageNo()
-	for i := 0; i < numPages; i++ {
-		p.Page()
+	// the anchor of the heading links to the top of it
+	if opts.Anchor != "" {
+		p.setAnchor(opts.Anchor, headingY, headingPage)
 	}
+
+	p.checkpoint("Heading created")
 }
 
 // ListItem defines an item to use in the List function
+// Spans can be given in place of Text to write the item as rich text.
 type ListItem struct {
 	Level int
 	Text  string
+	Spans []Span
+}
+
+// indents stop at this list level
+const maxListIndent = 10
+
+// used to get the x position of list items at a level
+func (p *Pdfb) listIndent(level int) float64 {
+	if level > maxListIndent {
+		level = maxListIndent
+	}
+	left, _ := p.contentEdges()
+	return left + p.indentSize*p.theme.List.Indent*float64(level)
 }
 
 // List is used for writing lists
 func (p *Pdfb) List(items []ListItem) {
-	// copy current font
+	// copy current font and foreground
 	currentFont := p.fontCopy(p.font)
-	maxIndent := 10
+	currentFG := p.foreground
+	maxIndent := maxListIndent
 
 	// loop through list items
 	for _, item := range items {
-		// indent in from margin (indents stop at level 8)
-		if item.Level <= maxIndent {
-			p.SetX(p.margin + p.indentSize*1.5*float64(item.Level))
-		} else {
-			p.SetX(p.margin + p.indentSize*1.5*float64(maxIndent))
-		}
+		// indent in from margin
+		p.SetX(p.listIndent(item.Level))
 
-		// switch to symbol font
+		// switch to symbol font, in the marker colour of the theme
 		p.font.Family = "zapfdingbats"
 		p.SetFont(p.font)
+		p.SetForeground(p.themeColour(p.theme.List.MarkerColour))
 
 		// switch case for bullet type
 		if item.Level <= maxIndent {
@@ -759,68 +814,191 @@ func (p *Pdfb) List(items []ListItem) {
 		// small indent in from the bullet symbol
 		p.SetX(p.GetX() + p.indentSize/1.25)
 
-		// change back to current font
+		// change back to current font and foreground
 		p.font.Size = currentFont.Size
 		p.SetFont(currentFont)
+		p.SetForeground(currentFG)
 
 		// print
-		p.pdf.MultiCell(0, p.lineHeight, item.Text, "", "", false)
+		p.listItemText(item)
 
 		// leave some space under each list item
-		p.SetY(p.GetY() + 2)
+		p.SetY(p.GetY() + p.mm(p.theme.List.ItemSpacing))
 	}
 
 	p.checkpoint("List printed")
 }
 
+// used to write the text of a list item from the cursor, wrapped lines
+// start at the same x as the first
+func (p *Pdfb) listItemText(item ListItem) {
+	if len(item.Spans) == 0 {
+		p.pdf.MultiCell(0, p.lineHeight, item.Text, "", "", false)
+		return
+	}
+
+	// rich text wraps back to the left margin, so the margin is moved to
+	// the text of the item while it is written
+	p.pdf.SetLeftMargin(p.GetX())
+	p.RichText(item.Spans...)
+	left, _ := p.sideMargins(p.pdf.PageNo())
+	p.pdf.SetLeftMargin(left)
+	if p.columns > 1 {
+		p.setColumnMargins()
+	}
+}
+
+// ListOptions defines how the items of an OrderedList are numbered
+// Formats gives the number format of each level starting at level 1, the
+// last format is used for any deeper levels. Hierarchical labels include
+// the numbers of the parent items, eg. 2.1.3
+type ListOptions struct {
+	Formats      []NumberFormat
+	Hierarchical bool
+	Start        int
+}
+
+// OrderedList is used for writing numbered lists
+// Wrapped lines are aligned with the text of the item, not its number
+func (p *Pdfb) OrderedList(items []ListItem, options ListOptions) {
+	if options.Start == 0 {
+		options.Start = 1
+	}
+	currentFG := p.foreground
+
+	// the format used for a level
+	format := func(level int) NumberFormat {
+		if len(options.Formats) == 0 {
+			return Decimal
+		}
+		if level > len(options.Formats) {
+			return options.Formats[len(options.Formats)-1]
+		}
+		return options.Formats[level-1]
+	}
+
+	// work out the label of each item, counters are reset when a
+	// parent item is numbered
+	labels := make([]string, len(items))
+	counters := []int{}
+	for i, item := range items {
+		level := item.Level
+		if level < 1 {
+			level = 1
+		}
+		for len(counters) < level {
+			counters = append(counters, 0)
+		}
+		counters = counters[:level]
+		if counters[level-1] == 0 && level == 1 {
+			counters[0] = options.Start
+		} else {
+			counters[level-1]++
+		}
+
+		if options.Hierarchical {
+			parts := make([]string, level)
+			for l, counter := range counters {
+				// a skipped level counts as its first item, so the next
+				// item at that level is its second
+				if counter == 0 {
+					counter = 1
+					counters[l] = counter
+				}
+				parts[l] = format(l + 1).Format(counter)
+			}
+			labels[i] = strings.Join(parts, ".")
+		} else {
+			labels[i] = format(level).Format(counters[level-1]) + "."
+		}
+	}
+
+	// the labels of each level share the width of the widest, so that
+	// the text of items at the same level lines up
+	labelWidths := map[int]float64{}
+	for i, item := range items {
+		if w := p.pdf.GetStringWidth(labels[i]); w > labelWidths[item.Level] {
+			labelWidths[item.Level] = w
+		}
+	}
+
+	for i, item := range items {
+		// keep the label on the same page as the first line of text
+		if p.GetY()+p.lineHeight > p.GetPageHeight()-p.bottomSpace() {
+			p.pageBreak()
+		}
+
+		// label, right aligned so that the dots line up
+		p.SetX(p.listIndent(item.Level))
+		p.SetForeground(p.themeColour(p.theme.List.MarkerColour))
+		p.pdf.CellFormat(labelWidths[item.Level], p.lineHeight, labels[i], "", 0, "MR", false, 0, "")
+		p.SetForeground(currentFG)
+
+		// small indent in from the label, wrapped lines start at the same x
+		p.SetX(p.GetX() + p.indentSize/1.25)
+		p.listItemText(item)
+
+		// leave some space under each list item
+		p.SetY(p.GetY() + p.mm(p.theme.List.ItemSpacing))
+	}
+
+	p.checkpoint("Ordered list printed")
+}
+
 // Image is used to insert an image
 // Use 0 in place of w or h to keep the aspect ratio
-func (p *Pdfb) Image(filename, align string, x, y, w, h float64) {
+func (p *Pdfb) Image(filename, align string, x, y, w, h float64, options ...ImageOptions) {
 	// check if image exists
 	if !fileExists(filename) {
-		log.ErrorFatal("Image could not be located (%s)", filename)
+		p.SetErrorf("%w: %s", ErrImageNotFound, filename)
+		return
 	}
 
-	// calc w and/or h values if 0 is given
-	if w == 0 {
-		info := p.pdf.RegisterImage(filename, "")
-		w = h * info.Width() / info.Height()
-	}
-	if h == 0 {
-		info := p.pdf.RegisterImage(filename, "")
-		h = w * info.Height() / info.Width()
-	}
+	opts := mergeImageOptions(options)
 
-	// align image for left, right, or centre
-	align = strings.ToLower(align)
-	switch {
-	case align == "l" || align == "left" || align == "":
-	case align == "c" || align == "centre":
-		x = p.GetX() + (p.GetPageWidth()-p.margin*2)/2 - w/2
-	case align == "r" || align == "right":
-		x = p.GetPageWidth() - p.margin - w
-	default:
-		log.ErrorFatal("Invalid alignment supplied to Image (%s)", align)
+	// register the image, filtered images are registered under their own name
+	name := filename
+	if len(opts.Filters) > 0 {
+		name = p.registerFiltered(filename, func() (image.Image, error) {
+			f, err := os.Open(filename)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			img, _, err := image.Decode(f)
+			return img, err
+		}, opts.Filters)
+	} else {
+		p.pdf.RegisterImage(filename, "")
+	}
+	if p.Err() {
+		return
 	}
 
-	// draw image
-	p.pdf.Image(filename, x, y, w, h, true, "", 0, "")
+	p.placeImage(name, align, x, y, w, h, opts.Caption)
 
 	p.checkpoint("Image printed")
 }
 
-// Debug is used for debugging purposes
+// Debug is used for debugging purposes, the message is sent to the logger
 func (p *Pdfb) Debug(str string) {
-	fmt.Println("-- Debug:", str)
+	p.logf(LogDebug, "%s", str)
 }
 
 // Hyperlink is used to print hyperlinks
 func (p *Pdfb) Hyperlink(displayText, url string) {
+	currentFont := p.fontCopy(p.font)
 	currentFG := p.GetForeground()
 
-	p.SetForeground("#00f")
+	// style the link from the theme
+	if p.theme.Link.Underline {
+		p.font.Underline = true
+		p.SetFont(p.font)
+	}
+	p.SetForeground(p.themeColour(p.theme.Link.Colour))
 	p.pdf.WriteLinkString(p.lineHeight, displayText, url)
 
+	p.SetFont(currentFont)
 	p.SetForeground(currentFG)
 
 	p.checkpoint("Hyperlink printed")
@@ -829,107 +1007,39 @@ func (p *Pdfb) Hyperlink(displayText, url string) {
 // This is the function that gets called before any "outputting" methods
 // such as SaveAs or ExportAs
 func (p *Pdfb) finalFunc() {
-	p.pdf.RegisterAlias("{pages}", strconv.Itoa(p.pdf.PageCount()))
-
-	// go back and write the ToC if necessary
-	if p.tocPage > 0 {
-		// needed to prevent writing a bookmark for 'contents' heading
-		p.writingContents = true
-		// go to toc page
-		p.pdf.SetPage(p.tocPage)
-		// go to top of page, under heading or margin
-		if p.headerHeight > 0 {
-			p.SetY(p.headerHeight)
-		} else {
-			p.SetY(p.margin)
-		}
-		p.Heading(1, "Contents")
-		p.lineHeight *= 1.5
-		var headingsPerPage int
-		var headingsPerPageSet bool
-		// slice is capped at the end because 'Contents' itself
-		// is a heading
-		for i, heading := range p.headings[:len(p.headings)-1] {
-			// handle overflows onto the next page
-			if !headingsPerPageSet && (p.GetY()+p.lineHeight) > (p.GetPageHeight()-p.footerHeight) {
-				headingsPerPage = i
-				headingsPerPageSet = true
-			}
-			// every time a multiple of headingsPerPage is hit, go to the next page
-			if headingsPerPageSet && i%headingsPerPage == 0 {
-				p.pdf.SetPage(p.pdf.PageNo() + 1)
-				p.SetY(p.headerHeight)
-			}
-
-			// set font to bold for level 1 headings
-			if heading.level == 1 {
-				p.font.Bold = true
-				p.SetFont(p.font)
-			} else {
-				p.font.Bold = false
-				p.SetFont(p.font)
-			}
-
-			headingIndent := p.indentSize * float64(heading.level-1)
-
-			// heading text
-			headingTextWidth := headingIndent + p.pdf.GetStringWidth(heading.text)
-
-			// heading page
-			headingPage := strconv.Itoa(heading.page)
-			headingPageWidth := p.pdf.GetStringWidth(headingPage)
-
-			// dots
-			pageWidth := p.GetPageWidth() - p.margin*2
-			dotSpace := pageWidth - headingTextWidth - headingPageWidth
-			var dots string
-			for {
-				if p.pdf.GetStringWidth(dots) >= dotSpace-0.75 {
-					break
-				}
-				dots += "."
-			}
-
-			//
-			//	print
-			//
+	if p.Err() {
+		return
+	}
 
-			if heading.level == 1 {
-				p.font.Bold = true
-				p.SetFont(p.font)
-			}
+	// content that is written from here on doesn't flow in columns
+	if p.columns > 1 {
+		p.SetColumns(1, 0)
+	}
 
-			// heading text
-			p.SetX(p.margin + headingIndent)
-			p.pdf.CellFormat(headingTextWidth-headingIndent, p.lineHeight, heading.text, "", 0, "L", false, heading.link, "")
+	// footnotes that didn't fit on the last page continue on new pages
+	for len(p.footnoteCarry) > 0 && !p.Err() {
+		p.pageBreak()
+	}
 
-			// dots
-			p.pdf.CellFormat(dotSpace, p.lineHeight, dots, "", 0, "C", false, 0, "")
+	// the layout is settled unless the ToC or references don't match
+	// the layout they were written with
+	p.settled = true
 
-			// heading page number
-			p.pdf.CellFormat(headingPageWidth, p.lineHeight, headingPage, "", 0, "R", false, 0, "")
-			p.Ln(1)
-		}
+	p.pdf.RegisterAlias("{pages}", strconv.Itoa(p.pdf.PageCount()))
+	p.registerSectionPages()
+	p.resolveRefs()
 
-		// go back to the end of the document before output
-		p.pdf.SetPage(p.pdf.PageCount())
-	}
+	// go back and write the ToC and other listings
+	p.writeListings()
 	p.checkpoint("Final func used")
 }
 
 // ExportAsBase64 is used to return a base64 encoding of the PDF
-func (p *Pdfb) ExportAsBase64() string {
-	p.finalFunc()
-	buf := new(bytes.Buffer)
-	err := p.pdf.Output(buf)
+func (p *Pdfb) ExportAsBase64() (string, error) {
+	b, err := p.Bytes()
 	if err != nil {
-		panic(err)
+		return "", err
 	}
 	p.checkpoint("Base64 encoding returned")
-	return base64.StdEncoding.EncodeToString(buf.Bytes())
-}
-
-// Error is used to print the PDF error
-func (p *Pdfb) Error() {
-	log.Info("PDF Error: %s", p.pdf.Error())
+	return base64.StdEncoding.EncodeToString(b), nil
 }
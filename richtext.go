@@ -0,0 +1,230 @@
+package pdfb
+
+import (
+	"strings"
+
+	"github.com/barjoio/utils/colour"
+)
+
+// Span defines a run of text with its own style, used by RichText
+// Family, Size and Colour use the current font and foreground when
+// left empty, and spans with a Link are written as hyperlinks in the
+// link style of the theme
+// eg. []Span{{Text: "Some "}, {Text: "bold", Bold: true}, {Text: " text."}}
+type Span struct {
+	Text          string
+	Family        string
+	Size          float64
+	Bold          bool
+	Italic        bool
+	Underline     bool
+	Strikethrough bool
+	Colour        string
+	Link          string
+}
+
+// a word, space or line break from a span, placed on a line
+type richFragment struct {
+	span    int
+	text    string
+	space   bool
+	newline bool
+	width   float64
+}
+
+// RichText is used to write a paragraph made up of spans of different styles
+// The text is wrapped across lines, and each line is as tall as the largest
+// text on it. Like WriteLn, the cursor is left at the start of the next line.
+func (p *Pdfb) RichText(spans ...Span) {
+	// copy current font and foreground
+	currentFont := p.fontCopy(p.font)
+	currentFG := p.foreground
+
+	// resolve the font of each span
+	fonts := make([]Font, len(spans))
+	for i, s := range spans {
+		fonts[i] = Font{
+			Family:        s.Family,
+			Size:          s.Size,
+			Bold:          s.Bold,
+			Italic:        s.Italic,
+			Underline:     s.Underline || (s.Link != "" && p.theme.Link.Underline),
+			Strikethrough: s.Strikethrough,
+		}
+		if fonts[i].Family == "" {
+			fonts[i].Family = currentFont.Family
+		}
+		if fonts[i].Size == 0 {
+			fonts[i].Size = currentFont.Size
+		}
+	}
+	useFont := func(i int) {
+		f := fonts[i]
+		p.font = f
+		p.pdf.SetFont(f.Family, p.makeFontStyleStr(), f.Size)
+	}
+
+	lines := p.richLines(spans, useFont)
+
+	left, _, _, _ := p.pdf.GetMargins()
+	x := p.GetX()
+	for _, line := range lines {
+		// the height of the line, each span sits on the baseline as it would if
+		// written on its own, so the line is tall enough for the largest text
+		// (an empty line is as tall as a line of the current font)
+		ascent, descent := 0.0, 0.0
+		if len(line) == 0 {
+			descent = p.lineHeight
+		}
+		for _, frag := range line {
+			useFont(frag.span)
+			_, fontSize := p.pdf.GetFontSize()
+			lh := p.lineHeight * fonts[frag.span].Size / currentFont.Size
+			a := lh/2 + fontSize*0.3
+			if a > ascent {
+				ascent = a
+			}
+			if lh-a > descent {
+				descent = lh - a
+			}
+		}
+
+		// break onto the next page or column if the line doesn't fit,
+		// keeping the indent of the line from the margin
+		if p.GetY()+ascent+descent > p.GetPageHeight()-p.bottomSpace() {
+			indent := x - left
+			p.pageBreak()
+			left, _, _, _ = p.pdf.GetMargins()
+			x = left + indent
+		}
+		y := p.GetY()
+
+		// draw runs of fragments from the same span together, so that
+		// underlines continue through spaces
+		for i := 0; i < len(line); {
+			j, run, width := i, "", 0.0
+			for ; j < len(line) && line[j].span == line[i].span; j++ {
+				run += line[j].text
+				width += line[j].width
+			}
+			s := spans[line[i].span]
+
+			useFont(line[i].span)
+			fg := currentFG
+			switch {
+			case s.Colour != "":
+				fg = s.Colour
+			case s.Link != "":
+				fg = p.themeColour(p.theme.Link.Colour)
+			}
+			p.pdf.SetTextColor(colour.HexToRGB(fg))
+			p.pdf.Text(x, y+ascent, run)
+			if s.Link != "" {
+				p.pdf.LinkString(x, y, width, ascent+descent, s.Link)
+			}
+
+			x += width
+			i = j
+		}
+
+		p.pdf.SetXY(left, y+ascent+descent)
+		x = left
+	}
+
+	// set the font and foreground back to how they were
+	p.font = currentFont
+	p.SetFont(currentFont)
+	p.SetForeground(currentFG)
+
+	p.checkpoint("Rich text written")
+}
+
+// RichParagraph is used to write a paragraph of spans (blank line after text)
+func (p *Pdfb) RichParagraph(spans ...Span) {
+	p.RichText(spans...)
+	p.Ln(1)
+}
+
+// used to split spans into lines that fit between the margins, the
+// first line starts at the cursor
+func (p *Pdfb) richLines(spans []Span, useFont func(i int)) (lines [][]richFragment) {
+	left, _, right, _ := p.pdf.GetMargins()
+	maxWidth := p.GetPageWidth() - right - left
+	available := p.GetPageWidth() - right - p.GetX()
+
+	var line []richFragment
+	var lineWidth float64
+	hasWord := false
+
+	// used to finish the current line, trailing spaces are dropped
+	endLine := func() {
+		for len(line) > 0 && line[len(line)-1].space {
+			line = line[:len(line)-1]
+		}
+		lines = append(lines, line)
+		line, lineWidth, hasWord = nil, 0, false
+		available = maxWidth
+	}
+
+	for i, s := range spans {
+		useFont(i)
+		for _, frag := range splitRichText(i, s.Text) {
+			switch {
+			case frag.newline:
+				endLine()
+				continue
+			case frag.space:
+				// spaces at the start of a wrapped line are dropped
+				if !hasWord && len(lines) > 0 {
+					continue
+				}
+			}
+
+			frag.width = p.pdf.GetStringWidth(frag.text)
+
+			if !frag.space && lineWidth+frag.width > available {
+				if hasWord || (len(lines) == 0 && available < maxWidth) {
+					endLine()
+				}
+			}
+
+			line = append(line, frag)
+			lineWidth += frag.width
+			if !frag.space {
+				hasWord = true
+			}
+		}
+	}
+	if len(line) > 0 {
+		endLine()
+	}
+
+	return
+}
+
+// used to split the text of a span into words, spaces and line breaks
+func splitRichText(span int, text string) (frags []richFragment) {
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			frags = append(frags, richFragment{span: span, text: word.String()})
+			word.Reset()
+		}
+	}
+
+	for _, r := range text {
+		switch r {
+		case '\n':
+			flush()
+			frags = append(frags, richFragment{span: span, newline: true})
+		case ' ', '\t':
+			flush()
+			frags = append(frags, richFragment{span: span, text: " ", space: true})
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+
+	return
+}
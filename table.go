@@ -0,0 +1,218 @@
+package pdfb
+
+import (
+	"strings"
+
+	"github.com/barjoio/utils/colour"
+)
+
+// TableColumn defines a column of a Table
+// Width gives the column a fixed width, when Width is 0 the column
+// shares the remaining width with the other unsized columns in
+// proportion to Weight (a Weight of 0 counts as 1)
+type TableColumn struct {
+	Header string
+	Width  float64
+	Weight float64
+	Align  string
+}
+
+// Table defines a table to use in the Table function
+// The header row is printed when any column has a Header, and is
+// repeated at the top of every page the table continues onto. A Caption
+// is numbered and written above the table, and is listed by ListOfTables.
+type Table struct {
+	Columns      []TableColumn
+	Rows         [][]string
+	Striped      bool
+	Borders      bool
+	BorderColour string
+	Caption      string
+}
+
+// the space between the edge of a cell and its text, in millimetres
+const tableCellPadding = 1.5
+
+// Table is used to print a table
+// Cell text is wrapped to fit the width of its column
+func (p *Pdfb) Table(t Table) {
+	if len(t.Columns) == 0 {
+		return
+	}
+
+	// copy current font and foreground
+	currentFont := p.fontCopy(p.font)
+	currentFG := p.foreground
+	currentDrawR, currentDrawG, currentDrawB := p.pdf.GetDrawColor()
+
+	if t.BorderColour == "" {
+		t.BorderColour = "#000"
+	}
+	p.pdf.SetDrawColor(colour.HexToRGB(t.BorderColour))
+
+	left, right := p.contentEdges()
+	widths := p.tableColumnWidths(t.Columns, right-left)
+
+	var caption string
+	captionHeight := 0.0
+	if t.Caption != "" {
+		caption = captionText("Table", len(p.tables)+1, t.Caption)
+		captionHeight = p.captionHeight(caption)
+	}
+
+	var header []string
+	for _, col := range t.Columns {
+		if col.Header != "" {
+			header = make([]string, len(t.Columns))
+			for i, col := range t.Columns {
+				header[i] = col.Header
+			}
+			break
+		}
+	}
+
+	// used to print the header row, in bold on the accent colour
+	printHeader := func() {
+		if header == nil {
+			return
+		}
+		p.font.Bold = true
+		p.SetFont(p.font)
+		p.SetForeground(p.background)
+		p.tableRow(t, widths, header, p.accentColour)
+		p.SetFont(currentFont)
+		p.SetForeground(currentFG)
+	}
+
+	// the caption, header and first row are kept together
+	headerHeight := 0.0
+	if header != nil {
+		p.font.Bold = true
+		p.SetFont(p.font)
+		headerHeight = p.tableRowHeight(widths, header)
+		p.SetFont(currentFont)
+	}
+	firstRowHeight := 0.0
+	if len(t.Rows) > 0 {
+		firstRowHeight = p.tableRowHeight(widths, t.Rows[0])
+	}
+	if p.GetY()+captionHeight+headerHeight+firstRowHeight > p.GetPageHeight()-p.bottomSpace() {
+		p.pageBreak()
+	}
+	if caption != "" {
+		p.addCaption(&p.tables, caption)
+		p.writeCaption(caption)
+	}
+	printHeader()
+
+	for i, row := range t.Rows {
+		// break onto the next page and repeat the header if the row doesn't fit
+		if p.GetY()+p.tableRowHeight(widths, row) > p.GetPageHeight()-p.bottomSpace() {
+			p.pageBreak()
+			printHeader()
+		}
+
+		// zebra striping, every other row is filled with a tint of the accent colour
+		fill := ""
+		if t.Striped && i%2 == 1 {
+			fill = tint(p.accentColour, 0.1)
+		}
+
+		p.tableRow(t, widths, row, fill)
+	}
+
+	p.pdf.SetDrawColor(currentDrawR, currentDrawG, currentDrawB)
+
+	p.checkpoint("Table printed")
+}
+
+// used to work out the widths of the columns of a table
+func (p *Pdfb) tableColumnWidths(columns []TableColumn, tableWidth float64) []float64 {
+	widths := make([]float64, len(columns))
+
+	// fixed widths are taken first
+	remaining := tableWidth
+	var totalWeight float64
+	for i, col := range columns {
+		if col.Width > 0 {
+			widths[i] = col.Width
+			remaining -= col.Width
+		} else if col.Weight > 0 {
+			totalWeight += col.Weight
+		} else {
+			totalWeight++
+		}
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	// the remaining width is shared out by weight
+	for i, col := range columns {
+		if col.Width > 0 {
+			continue
+		}
+		weight := col.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		widths[i] = remaining * weight / totalWeight
+	}
+
+	return widths
+}
+
+// used to get the height of a table row, which is the height of its
+// tallest cell
+func (p *Pdfb) tableRowHeight(widths []float64, cells []string) float64 {
+	padding := p.mm(tableCellPadding)
+	lines := 1
+	for i, w := range widths {
+		if i >= len(cells) {
+			break
+		}
+		if n := len(p.splitText(cells[i], w-padding*2)); n > lines {
+			lines = n
+		}
+	}
+	return float64(lines)*p.lineHeight + padding*2
+}
+
+// used to print a row of a table at the cursor, fill is the
+// background colour of the row ("" for none)
+func (p *Pdfb) tableRow(t Table, widths []float64, cells []string, fill string) {
+	x, _ := p.contentEdges()
+	y := p.GetY()
+	h := p.tableRowHeight(widths, cells)
+	padding := p.mm(tableCellPadding)
+
+	for i, w := range widths {
+		var styleStr string
+		if fill != "" {
+			styleStr += "F"
+			p.pdf.SetFillColor(colour.HexToRGB(fill))
+		}
+		if t.Borders {
+			styleStr += "D"
+		}
+		if styleStr != "" {
+			p.pdf.Rect(x, y, w, h, styleStr)
+		}
+
+		if i < len(cells) {
+			align := "L"
+			if t.Columns[i].Align != "" {
+				align = p.makeAlignStr(t.Columns[i].Align)
+			}
+			for j, line := range p.splitText(cells[i], w-padding*2) {
+				p.pdf.SetXY(x+padding, y+padding+float64(j)*p.lineHeight)
+				p.pdf.CellFormat(w-padding*2, p.lineHeight, strings.TrimSpace(line), "", 0, "M"+align, false, 0, "")
+			}
+		}
+
+		x += w
+	}
+
+	// move the cursor below the row
+	p.pdf.SetY(y + h)
+}
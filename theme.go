@@ -0,0 +1,178 @@
+package pdfb
+
+// Accent can be used in place of a hex colour in a Theme to use the
+// accent colour of the document
+const Accent = "accent"
+
+// TextStyle defines the font and colour of text
+// An empty font Family or Size keeps the current one, and an empty
+// Colour keeps the current foreground
+type TextStyle struct {
+	Font   Font
+	Colour string
+}
+
+// HeadingStyle defines the look of a heading level
+// SpaceBefore and SpaceAfter are given in lines of the heading, and the
+// rule is a line drawn across the page under the heading (a RuleWeight
+// of 0 is drawn 0.5mm thick)
+type HeadingStyle struct {
+	Font        Font
+	Colour      string
+	SpaceBefore float64
+	SpaceAfter  float64
+	Rule        bool
+	RuleColour  string
+	RuleWeight  float64
+}
+
+// LinkStyle defines the look of hyperlinks
+type LinkStyle struct {
+	Colour    string
+	Underline bool
+}
+
+// ListStyle defines the look of lists
+// Indent is the indent of each level as a multiple of the indent size, and
+// ItemSpacing is the space left under each item, in millimetres
+type ListStyle struct {
+	Indent       float64
+	ItemSpacing  float64
+	MarkerColour string
+}
+
+// Theme defines the look of a document
+// Colours are hex strings, or Accent for the accent colour. Lengths are in
+// millimetres whatever the unit of the document, so that a theme looks the
+// same in any document.
+type Theme struct {
+	Accent     string
+	Body       TextStyle
+	Headings   [6]HeadingStyle
+	Link       LinkStyle
+	Header     TextStyle
+	Footer     TextStyle
+	Code       TextStyle
+	Caption    TextStyle
+	Footnote   TextStyle
+	IndexGroup TextStyle
+	List       ListStyle
+}
+
+// DefaultTheme is the theme used by New
+func DefaultTheme() Theme {
+	return Theme{
+		Accent: "#f00",
+		Body:   TextStyle{Font: Font{Family: defaultFontFamily, Size: 12}, Colour: "#000000"},
+		Headings: [6]HeadingStyle{
+			{Font: Font{Bold: true, Size: 19.5}, Colour: Accent, SpaceAfter: 0.25, Rule: true, RuleColour: Accent, RuleWeight: 0.5},
+			{Font: Font{Bold: true, Size: 17}, SpaceAfter: 0.1},
+			{Font: Font{Bold: true, Size: 15}, SpaceAfter: 0.1},
+			{Font: Font{Bold: true, Size: 13.5}, SpaceAfter: 0.1},
+			{Font: Font{Bold: true, Size: 12.5}, SpaceAfter: 0.1},
+			{Font: Font{Bold: true, Size: 12}, SpaceAfter: 0.1},
+		},
+		Link:       LinkStyle{Colour: "#00f"},
+		Header:     TextStyle{Font: Font{Size: 12}, Colour: "#000"},
+		Footer:     TextStyle{Font: Font{Size: 12}, Colour: "#000"},
+		Code:       TextStyle{Font: Font{Family: "Courier"}},
+		Caption:    TextStyle{Font: Font{Italic: true, Size: 10}},
+		Footnote:   TextStyle{Font: Font{Size: 9}},
+		IndexGroup: TextStyle{Font: Font{Bold: true}, Colour: Accent},
+		List:       ListStyle{Indent: 1.5, ItemSpacing: 2},
+	}
+}
+
+// ClassicTheme is a serif theme in the style of a printed report
+func ClassicTheme() Theme {
+	return Theme{
+		Accent: "#7a1f1f",
+		Body:   TextStyle{Font: Font{Family: "Times", Size: 12}, Colour: "#111111"},
+		Headings: [6]HeadingStyle{
+			{Font: Font{Family: "Times", Bold: true, Size: 22}, Colour: Accent, SpaceBefore: 0.5, SpaceAfter: 0.3, Rule: true, RuleColour: "#111111", RuleWeight: 0.3},
+			{Font: Font{Family: "Times", Bold: true, Size: 17}, Colour: Accent, SpaceBefore: 0.3, SpaceAfter: 0.15},
+			{Font: Font{Family: "Times", Bold: true, Italic: true, Size: 15}, SpaceBefore: 0.2, SpaceAfter: 0.1},
+			{Font: Font{Family: "Times", Bold: true, Size: 13}, SpaceAfter: 0.1},
+			{Font: Font{Family: "Times", Italic: true, Size: 12.5}, SpaceAfter: 0.1},
+			{Font: Font{Family: "Times", Italic: true, Size: 12}, SpaceAfter: 0.1},
+		},
+		Link:       LinkStyle{Colour: Accent, Underline: true},
+		Header:     TextStyle{Font: Font{Family: "Times", Italic: true, Size: 10}, Colour: "#444444"},
+		Footer:     TextStyle{Font: Font{Family: "Times", Size: 10}, Colour: "#444444"},
+		Code:       TextStyle{Font: Font{Family: "Courier"}},
+		Caption:    TextStyle{Font: Font{Family: "Times", Italic: true, Size: 10.5}},
+		Footnote:   TextStyle{Font: Font{Family: "Times", Size: 9.5}},
+		IndexGroup: TextStyle{Font: Font{Family: "Times", Bold: true}, Colour: Accent},
+		List:       ListStyle{Indent: 1.5, ItemSpacing: 1.5, MarkerColour: Accent},
+	}
+}
+
+// ModernTheme is a sans-serif theme with a blue accent and quiet headers
+func ModernTheme() Theme {
+	return Theme{
+		Accent: "#1e66f5",
+		Body:   TextStyle{Font: Font{Family: "Helvetica", Size: 11}, Colour: "#222222"},
+		Headings: [6]HeadingStyle{
+			{Font: Font{Family: "Helvetica", Bold: true, Size: 24}, Colour: "#111111", SpaceBefore: 0.4, SpaceAfter: 0.2},
+			{Font: Font{Family: "Helvetica", Bold: true, Size: 17}, Colour: Accent, SpaceBefore: 0.3, SpaceAfter: 0.1},
+			{Font: Font{Family: "Helvetica", Bold: true, Size: 14}, Colour: "#111111", SpaceBefore: 0.2, SpaceAfter: 0.1},
+			{Font: Font{Family: "Helvetica", Bold: true, Size: 12}, Colour: "#111111", SpaceAfter: 0.1},
+			{Font: Font{Family: "Helvetica", Bold: true, Size: 11}, Colour: "#444444", SpaceAfter: 0.1},
+			{Font: Font{Family: "Helvetica", Size: 11}, Colour: "#444444", SpaceAfter: 0.1},
+		},
+		Link:       LinkStyle{Colour: Accent},
+		Header:     TextStyle{Font: Font{Family: "Helvetica", Size: 9}, Colour: "#666666"},
+		Footer:     TextStyle{Font: Font{Family: "Helvetica", Size: 9}, Colour: "#666666"},
+		Code:       TextStyle{Font: Font{Family: "Courier"}, Colour: "#333333"},
+		Caption:    TextStyle{Font: Font{Family: "Helvetica", Size: 9.5}, Colour: "#444444"},
+		Footnote:   TextStyle{Font: Font{Family: "Helvetica", Size: 8.5}, Colour: "#444444"},
+		IndexGroup: TextStyle{Font: Font{Family: "Helvetica", Bold: true}, Colour: Accent},
+		List:       ListStyle{Indent: 1.25, ItemSpacing: 1.5, MarkerColour: Accent},
+	}
+}
+
+// WithTheme is used to set the theme when creating a document
+func WithTheme(theme Theme) Option {
+	return func(p *Pdfb) {
+		p.SetTheme(theme)
+	}
+}
+
+// SetTheme is used to set the theme
+// The accent colour, body font and foreground are set from the theme
+func (p *Pdfb) SetTheme(theme Theme) {
+	p.theme = theme
+
+	if theme.Accent != "" {
+		p.accentColour = theme.Accent
+	}
+	p.SetFont(theme.Body.Font)
+	if theme.Body.Colour != "" {
+		p.SetForeground(theme.Body.Colour)
+	}
+
+	p.checkpoint("Theme set")
+}
+
+// GetTheme is used to get the theme
+func (p *Pdfb) GetTheme() Theme {
+	return p.theme
+}
+
+// used to get the hex colour of a theme colour, Accent is the accent
+// colour and an empty colour is the current foreground
+func (p *Pdfb) themeColour(c string) string {
+	switch c {
+	case "":
+		return p.foreground
+	case Accent:
+		return p.accentColour
+	}
+	return c
+}
+
+// used to set the font and foreground to a text style
+func (p *Pdfb) useTextStyle(style TextStyle) {
+	p.SetFont(style.Font)
+	p.SetForeground(p.themeColour(style.Colour))
+}
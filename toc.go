@@ -0,0 +1,245 @@
+package pdfb
+
+import "strings"
+
+// ToCOptions defines how a table of contents is written
+// Title defaults to "Contents", and is not written when NoTitle is set.
+// Headings deeper than MaxDepth are left out (0 lists every level). Leader
+// is the text repeated between each heading and its page number, which
+// defaults to "." and is left out when NoLeader is set. Levels gives the
+// style of each level starting at level 1, the last style is used for any
+// deeper levels, and by default level 1 is bold and each level is indented
+// by the indent size. Pages is the number of pages set aside for the
+// contents, which defaults to 1, and is only needed for documents that
+// aren't built using Generate, which sets aside as many as are needed.
+type ToCOptions struct {
+	Title    string
+	NoTitle  bool
+	MaxDepth int
+	Leader   string
+	NoLeader bool
+	Levels   []ToCLevelStyle
+	Pages    int
+}
+
+// ToCLevelStyle defines the look of the entries of a heading level in a
+// table of contents
+// An empty font Family or Size keeps the current one, and an empty Colour
+// keeps the current foreground. Indent is the distance from the margin.
+type ToCLevelStyle struct {
+	Font   Font
+	Indent float64
+	Colour string
+}
+
+// listing is used to define a list of entries and their page numbers, such
+// as the ToC, that is written onto pages set aside for it when the document
+// is output
+type listing struct {
+	page        int
+	pages       int
+	pagesNeeded int
+	options     ToCOptions
+	entries     func() []heading
+}
+
+// used to fill in the defaults of ToC options, the entries of a ToC
+// are styled by heading level unless levels is given
+func (p *Pdfb) mergeToCOptions(options []ToCOptions, title string, levels []ToCLevelStyle) ToCOptions {
+	var opts ToCOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.Title == "" {
+		opts.Title = title
+	}
+	if opts.Leader == "" {
+		opts.Leader = "."
+	}
+	if len(opts.Levels) == 0 {
+		opts.Levels = levels
+	}
+	if len(opts.Levels) == 0 {
+		for level := 1; level <= 6; level++ {
+			opts.Levels = append(opts.Levels, ToCLevelStyle{
+				Font:   Font{Bold: level == 1},
+				Indent: p.indentSize * float64(level-1),
+			})
+		}
+	}
+	return opts
+}
+
+// ToC is used to set aside pages for a table of contents, which is written
+// from the headings when the document is output
+// The contents start on the current page, and the content that follows
+// starts on a new page after them. The number of pages set in the options
+// is set aside, or as many as the contents need when the document is built
+// using Generate. If the contents don't fit on the pages set aside, an error
+// wrapping ErrToCOverflow is set.
+//
+// Eg. ToC(ToCOptions{Pages: 3})
+func (p *Pdfb) ToC(options ...ToCOptions) {
+	p.addListing(p.mergeToCOptions(options, "Contents", nil), func() []heading {
+		return p.headings
+	})
+	p.checkpoint("ToC pages added")
+}
+
+// used to set aside pages for a listing, the number of pages is taken from
+// the options, or from the last pass of Generate
+func (p *Pdfb) addListing(options ToCOptions, entries func() []heading) {
+	pages := options.Pages
+	if pages < 1 {
+		pages = 1
+	}
+	if i := len(p.listings); i < len(p.listingPages) {
+		pages = p.listingPages[i]
+	}
+
+	// the listing starts on the current page, so one is needed
+	if p.pdf.PageNo() == 0 {
+		p.Page()
+	}
+	p.listings = append(p.listings, &listing{
+		page:    p.pdf.PageNo(),
+		pages:   pages,
+		options: options,
+		entries: entries,
+	})
+	for i := 0; i < pages; i++ {
+		p.Page()
+	}
+}
+
+// used to write the listings onto the pages set aside for them, the
+// entries are taken first since the titles of the listings are headings
+func (p *Pdfb) writeListings() {
+	entries := make([][]heading, len(p.listings))
+	for i, l := range p.listings {
+		entries[i] = l.entries()
+	}
+
+	for i, l := range p.listings {
+		p.writeListing(l, entries[i])
+		if l.pagesNeeded != l.pages {
+			p.settled = false
+		}
+		if l.pagesNeeded > l.pages && !p.generating {
+			p.SetErrorf("%w: %d pages needed, %d reserved", ErrToCOverflow, l.pagesNeeded, l.pages)
+		}
+	}
+}
+
+// used to write a listing onto the pages set aside for it, entries that
+// don't fit on those pages are counted but not written, so that the
+// number of pages needed is known
+func (p *Pdfb) writeListing(l *listing, entries []heading) {
+	opts := l.options
+	currentLH := p.lineHeight
+	currentFont := p.fontCopy(p.font)
+	currentFG := p.foreground
+
+	// needed to prevent writing a bookmark for 'contents' heading
+	p.writingContents = true
+
+	// the footnotes of the last page take no space on the listing pages,
+	// and automatic page breaks are turned off since gofpdf would break at
+	// the bottom of the last page, which may be a different size, entries
+	// that don't fit are handled below instead
+	currentFootnoteSpace := p.footnoteSpace
+	p.footnoteSpace = 0
+	p.pdf.SetAutoPageBreak(false, 0)
+	currentLeft, _, currentRight, _ := p.pdf.GetMargins()
+
+	// go to top of the first page, under heading or margin, the font is set
+	// again since the page was finished with a different one
+	page, lastPage := l.page, l.page+l.pages-1
+	offset := p.revisitPage(page)
+	p.SetFont(p.font)
+	p.SetY(p.topSpace())
+	if !opts.NoTitle {
+		p.Heading(1, opts.Title)
+	}
+	p.lineHeight *= 1.5
+
+	left, right := p.contentEdges()
+	for _, heading := range entries {
+		if opts.MaxDepth > 0 && heading.level > opts.MaxDepth {
+			continue
+		}
+
+		// set the font of the level, which sets the height of the entry
+		style := opts.Levels[len(opts.Levels)-1]
+		if heading.level <= len(opts.Levels) {
+			style = opts.Levels[heading.level-1]
+		}
+		font := style.Font
+		if font.Family == "" {
+			font.Family = currentFont.Family
+		}
+		if font.Size == 0 {
+			font.Size = currentFont.Size
+		}
+		p.SetFont(font)
+
+		// overflow onto the next page when the entry doesn't fit
+		if p.GetY()+p.lineHeight > p.GetPageHeight()-p.bottomSpace() {
+			page++
+			p.leavePage(offset)
+			offset = 0
+			if page <= lastPage {
+				offset = p.revisitPage(page)
+				p.SetFont(p.font)
+			}
+			p.SetY(p.topSpace())
+		}
+		if page > lastPage {
+			p.SetY(p.GetY() + p.lineHeight)
+			continue
+		}
+
+		p.SetForeground(p.themeColour(style.Colour))
+
+		// widths of the heading text and page number, the leader fills
+		// the space between them
+		headingText := heading.text
+		headingTextWidth := p.pdf.GetStringWidth(headingText)
+		headingPage := p.pageLabel(heading.page)
+		headingPageWidth := p.pdf.GetStringWidth(headingPage)
+		leaderSpace := right - left - style.Indent - headingTextWidth - headingPageWidth
+		var leader string
+		if !opts.NoLeader {
+			if w := p.pdf.GetStringWidth(opts.Leader); w > 0 && leaderSpace > w {
+				leader = strings.Repeat(opts.Leader, int((leaderSpace-p.mm(0.75))/w))
+			}
+		}
+
+		//
+		//	print
+		//
+
+		// heading text, leader and page number all link to the heading
+		p.SetX(left + style.Indent)
+		p.pdf.Link(left+style.Indent, p.GetY()+offset, right-left-style.Indent, p.lineHeight, heading.link)
+		p.pdf.CellFormat(headingTextWidth, p.lineHeight, headingText, "", 0, "L", false, 0, "")
+		p.pdf.CellFormat(leaderSpace, p.lineHeight, leader, "", 0, "R", false, 0, "")
+		p.pdf.CellFormat(headingPageWidth, p.lineHeight, headingPage, "", 0, "R", false, 0, "")
+		p.Ln(1)
+	}
+	l.pagesNeeded = page - l.page + 1
+	p.leavePage(offset)
+
+	// go back to the end of the document before output
+	p.pdf.SetPage(p.pdf.PageCount())
+	p.SetFont(currentFont)
+	p.SetForeground(currentFG)
+	p.lineHeight = currentLH
+	p.footnoteSpace = currentFootnoteSpace
+	p.setPageBreak()
+	p.pdf.SetLeftMargin(currentLeft)
+	p.pdf.SetRightMargin(currentRight)
+	p.writingContents = false
+
+	p.checkpoint("Listing written")
+}
@@ -0,0 +1,54 @@
+package pdfb
+
+// the number of each unit in a millimetre
+var unitsPerMM = map[string]float64{
+	"mm": 1,
+	"cm": 0.1,
+	"in": 1 / 25.4,
+	"pt": 72 / 25.4,
+}
+
+// WithUnit is used to set the unit of the document when creating it, which
+// is used for every position, length and size, eg. of Box, Line, Image,
+// SetX and SetY, the margin, the line height and the page size
+// The unit is either "mm" (the default), "cm", "in" or "pt". Font sizes are
+// always in points, and the lengths of a Theme are in millimetres. The unit
+// should be the first option, since the document is set up again in it.
+// An error wrapping ErrInvalidUnit is set if the unit is not recognised.
+func WithUnit(unit string) Option {
+	return func(p *Pdfb) {
+		perMM, ok := unitsPerMM[unit]
+		if !ok {
+			p.SetErrorf("%w: %s", ErrInvalidUnit, unit)
+			return
+		}
+
+		// lengths that have already been set are changed to the new unit
+		scale := perMM / unitsPerMM[p.unit]
+		p.margins.Top *= scale
+		p.margins.Bottom *= scale
+		p.margins.Left *= scale
+		p.margins.Right *= scale
+		p.margins.Gutter *= scale
+		p.lineHeight *= scale
+		p.indentSize *= scale
+		p.pageWidth *= scale
+		p.pageHeight *= scale
+		p.headerHeight *= scale
+		p.footerHeight *= scale
+		p.columnGutter *= scale
+
+		p.unit = unit
+		p.initPdf()
+	}
+}
+
+// GetUnit is used to get the unit of the document
+func (p *Pdfb) GetUnit() string {
+	return p.unit
+}
+
+// used to convert a length in millimetres into the unit of the document
+func (p *Pdfb) mm(length float64) float64 {
+	return length * unitsPerMM[p.unit]
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"unicode"
 
-	"github.com/barjoio/utils/log"
+	"github.com/barjoio/utils/colour"
+	"github.com/jung-kurt/gofpdf"
 )
 
 // Used to decode base64 encoded string
@@ -24,12 +26,11 @@ func fileExists(f string) bool {
 	return true
 }
 
-// Used to report any errors or display a success message
+// Used to trace a successful operation, errors are kept by the pdf and
+// reported through Error
 func (p *Pdfb) checkpoint(str string) {
-	if p.pdf.Err() {
-		log.ErrorFatal(p.pdf.Error().Error())
-	} else {
-		fmt.Println("-- Checkpoint:", str)
+	if !p.Err() {
+		p.logf(LogDebug, "Checkpoint: %s", str)
 	}
 }
 
@@ -45,8 +46,156 @@ func (p *Pdfb) makeAlignStr(alignInput string) (alignStr string) {
 	case alignInput == "r" || alignInput == "right":
 		alignStr = "R"
 	default:
-		log.ErrorFatal("Invalid align input (%s)", alignInput)
+		p.SetErrorf("%w: %s", ErrInvalidAlign, alignInput)
 	}
 
 	return
 }
+
+// used to get the space at the top of the page, which is either the
+// height of the header if present, or the margin
+func (p *Pdfb) topSpace() float64 {
+	if p.headerHeight > 0 {
+		return p.headerHeight
+	}
+	return p.margins.Top
+}
+
+// used to get the space at the bottom of the page, which is either the
+// height of the footer if present, or the margin, along with the space set
+// aside for footnotes
+func (p *Pdfb) bottomSpace() float64 {
+	return p.footerSpace() + p.footnoteSpace
+}
+
+// used to get the space at the bottom of the page under the footnotes,
+// which is either the height of the footer if present, or the margin
+func (p *Pdfb) footerSpace() float64 {
+	if p.footerHeight > 0 {
+		return p.footerHeight
+	}
+	return p.margins.Bottom
+}
+
+// used to set the space from the bottom of the page where the auto page
+// break gets triggered
+func (p *Pdfb) setPageBreak() {
+	p.pdf.SetAutoPageBreak(true, p.bottomSpace())
+}
+
+// used to get the size of the current page, which is the size of the pages
+// that will be added when there are none yet
+// The size is looked up by page since gofpdf keeps the size of the last page
+// added when going back to an earlier page.
+func (p *Pdfb) currentPageSize() (w, h float64) {
+	page := p.pdf.PageNo()
+	if page == 0 {
+		if p.orientation == "L" {
+			return p.pageHeight, p.pageWidth
+		}
+		return p.pageWidth, p.pageHeight
+	}
+	w, h, _ = p.pdf.PageSize(page)
+	return
+}
+
+// used to go back to an earlier page to draw on it, gofpdf places what is
+// drawn using the height of the last page added, so on a page of a different
+// height what is drawn is moved to where it belongs
+// The margins are set to those of the page. The offset is returned to place
+// links, which aren't moved, and is passed to leavePage once drawing on the
+// page is done.
+func (p *Pdfb) revisitPage(page int) (offset float64) {
+	p.pdf.SetPage(page)
+	left, right := p.sideMargins(page)
+	p.pdf.SetLeftMargin(left)
+	p.pdf.SetRightMargin(right)
+
+	_, lastHeight := p.pdf.GetPageSize()
+	_, height := p.currentPageSize()
+	offset = lastHeight - height
+	if offset != 0 {
+		p.pdf.TransformBegin()
+		p.pdf.TransformTranslateY(offset)
+	}
+	return
+}
+
+// used to finish drawing on a page gone back to with revisitPage
+func (p *Pdfb) leavePage(offset float64) {
+	if offset != 0 {
+		p.pdf.TransformEnd()
+	}
+}
+
+// used to get the left and right edges of the area that content flows
+// into, which is the current column when columns are being used
+func (p *Pdfb) contentEdges() (left, right float64) {
+	left, _, rightMargin, _ := p.pdf.GetMargins()
+	return left, p.GetPageWidth() - rightMargin
+}
+
+// used to get the distance of the cursor from the left margin, which is
+// kept when the cursor moves to a new column or page
+func (p *Pdfb) marginIndent() float64 {
+	left, _ := p.contentEdges()
+	return p.GetX() - left
+}
+
+// used to break onto a new page the same size as the current page,
+// in the same way that an automatic page break does, when columns are
+// being used this moves to the next column instead
+func (p *Pdfb) pageBreak() {
+	if !p.acceptPageBreak() {
+		return
+	}
+	w, h := p.currentPageSize()
+	p.pdf.AddPageFormat("P", gofpdf.SizeType{Wd: w, Ht: h})
+	p.checkpoint("Page break inserted")
+}
+
+// used to mix a colour with white, amount is the fraction of
+// the original colour that is kept (0-1)
+func tint(hex string, amount float64) string {
+	r, g, b := colour.HexToRGB(hex)
+	mix := func(c int) int {
+		return int(float64(c)*amount + 255*(1-amount) + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", mix(r), mix(g), mix(b))
+}
+
+// used to split text into lines that fit in a width, the same way as the
+// pdf's SplitText, which can't measure characters outside of the core fonts
+func (p *Pdfb) splitText(text string, width float64) (lines []string) {
+	width -= 2 * p.pdf.GetCellMargin()
+	s := []rune(strings.TrimRight(text, "\n"))
+	sep, start, i := -1, 0, 0
+	var w float64
+	for i < len(s) {
+		c := s[i]
+		w += p.pdf.GetStringWidth(string(c))
+		if unicode.IsSpace(c) {
+			sep = i
+		}
+		if c != '\n' && w <= width {
+			i++
+			continue
+		}
+
+		// break at the last space, or before the character if there isn't one
+		if sep == -1 {
+			if i == start {
+				i++
+			}
+			sep = i
+		} else {
+			i = sep + 1
+		}
+		lines = append(lines, string(s[start:sep]))
+		sep, start, w = -1, i, 0
+	}
+	if i != start {
+		lines = append(lines, string(s[start:i]))
+	}
+	return lines
+}